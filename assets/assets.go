@@ -0,0 +1,9 @@
+// Package assets embarque les ressources par défaut du jeu dans le binaire
+package assets
+
+import "embed"
+
+// FS contient les images, sons, musiques et polices livrés avec le jeu
+//
+//go:embed *.png *.mp3 *.ttf *.json
+var FS embed.FS
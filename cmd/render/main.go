@@ -0,0 +1,17 @@
+// Commande render : rejoue une partie enregistrée et l'enregistre en GIF, ou en PNG animé si le fichier
+// de sortie se termine par .png
+// Contrairement à la sous-commande render du jeu, elle n'importe pas ebiten et fonctionne donc sans écran
+// ni carte graphique
+//
+//	go run ./cmd/render partie.json -o partie.gif
+package main
+
+import (
+	"os"
+
+	"snake-go/src/replay/render"
+)
+
+func main() {
+	render.Command("render", os.Args[1:])
+}
@@ -1,38 +1,67 @@
 package main
 
 import (
+	"flag"
 	"image"
-	_ "image/png"
 	"log"
+	"os"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 
 	"snake-go/src/audio"
+	"snake-go/src/audio/device"
+	"snake-go/src/config"
 	"snake-go/src/constants"
 	"snake-go/src/game"
+	"snake-go/src/i18n"
+	"snake-go/src/profile"
+	"snake-go/src/replay/render"
+	"snake-go/src/resources"
+	"snake-go/src/theme"
+	"snake-go/src/ui"
 )
 
-func loadIcon() image.Image {
-	icon, _, err := ebitenutil.NewImageFromFile("assets/icone.png")
-	if err != nil {
-		log.Fatal(err)
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "render" {
+		render.Command("snake-go render", os.Args[2:])
+		return
 	}
 
-	return icon
-}
+	cfg := config.Load()
+
+	// les options de la ligne de commande remplacent la configuration enregistrée
+	flag.IntVar(&cfg.BoardWidth, "board-width", cfg.BoardWidth, "largeur du plateau en cellules (10 à 100)")
+	flag.IntVar(&cfg.BoardHeight, "board-height", cfg.BoardHeight, "hauteur du plateau en cellules (10 à 100)")
+	flag.BoolVar(&cfg.Fullscreen, "fullscreen", cfg.Fullscreen, "lancer le jeu en plein écran")
+	flag.StringVar(&cfg.AssetsDir, "assets", cfg.AssetsDir, "dossier de ressources remplaçant les ressources embarquées")
+	perf := flag.Bool("perf", false, "afficher le temps et les allocations de chaque image")
+	noAudio := flag.Bool("no-audio", false, "jouer sans carte son, les sons ne sont pas décodés")
+	flag.Parse()
+	cfg.Normalize()
 
-func main() {
 	ebiten.SetWindowSize(constants.ScreenWidth, constants.ScreenHeight)
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	ebiten.SetFullscreen(cfg.Fullscreen)
 	ebiten.SetWindowTitle("Snake Go")
 
-	icon := loadIcon()
-	ebiten.SetWindowIcon([]image.Image{icon})
+	resources.Init(cfg.AssetsDir)
+	ebiten.SetWindowIcon([]image.Image{resources.IconImage})
+	theme.Init(config.ThemesDir(), cfg.Theme)
+	i18n.LoadDir(config.LocalesDir())
+	i18n.SetLanguage(cfg.Language)
 
-	audio.InitAudio()
+	var sound audio.Backend = audio.NewNull()
+	if !*noAudio {
+		sound = device.New(constants.SampleRate)
+	}
+	audio.InitAudio(sound, resources.ReadFile, config.MusicDir(), config.SoundPresetsPath())
+	current := theme.Current()
+	audio.ApplyTheme(current.ReadFile, audio.SoundFiles(current.Sounds))
+	game.ApplyAudioSettings(cfg)
+
+	profiles := profile.Load(config.ProfilesPath())
 
 	g := &game.Game{
-		GridManager:    game.NewGrid(constants.CellSize),
 		Score:          0,
 		UpdateInterval: 3,
 		Scores: []game.Score{
@@ -40,8 +69,14 @@ func main() {
 			{Value: 23, Name: "Joueur2"},
 			{Value: 12, Name: "Joueur3"},
 		},
-		State:      game.Menu,
-		PlayerName: "",
+		State:    game.Menu,
+		Config:   cfg,
+		Profiles: profiles,
+		Profile:  profiles.LastUsed(),
+	}
+
+	if *perf {
+		g.Perf = &ui.PerfOverlay{}
 	}
 
 	if err := ebiten.RunGame(g); err != nil {
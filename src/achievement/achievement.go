@@ -0,0 +1,103 @@
+// Package achievement décrit les succès du jeu et les débloque à partir des événements d'une partie
+//
+// Les succès sont déclarés dans definitions.json : chacun est vérifié à chaque événement de son déclencheur,
+// éventuellement limité à un mode ou une difficulté, et débloqué quand toutes ses conditions sont remplies.
+// Leur nom et leur description sont traduits avec les clés "achievement.<id>.name" et "achievement.<id>.description".
+package achievement
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log"
+	"time"
+
+	"snake-go/src/i18n"
+)
+
+// Déclencheurs des succès
+const (
+	FoodEaten = "food_eaten" // valeurs : length, score, apples_total, lives_lost
+	GameEnded = "game_ended" // valeurs : score, apples, length, lives_lost, detours, duration_seconds, games_played
+)
+
+//go:embed definitions.json
+var definitionsJSON []byte
+
+// Condition compare une valeur de l'événement à des bornes, incluses
+type Condition struct {
+	Metric string `json:"metric"`
+	Min    *int   `json:"min,omitempty"`
+	Max    *int   `json:"max,omitempty"`
+}
+
+// Definition décrit un succès
+type Definition struct {
+	ID         string      `json:"id"`
+	Trigger    string      `json:"trigger"`
+	Mode       string      `json:"mode,omitempty"`       // tous les modes si vide
+	Difficulty string      `json:"difficulty,omitempty"` // toutes les difficultés si vide
+	Conditions []Condition `json:"conditions"`
+}
+
+// Name retourne le nom traduit du succès
+func (d *Definition) Name() string {
+	return i18n.T("achievement." + d.ID + ".name")
+}
+
+// Description retourne la description traduite du succès
+func (d *Definition) Description() string {
+	return i18n.T("achievement." + d.ID + ".description")
+}
+
+// Event est un événement d'une partie, avec les valeurs que les conditions peuvent tester
+type Event struct {
+	Trigger    string
+	Mode       string
+	Difficulty string
+	Values     map[string]int
+}
+
+// Définitions des succès, dans l'ordre de la galerie
+var definitions []*Definition
+
+func init() {
+	if err := json.Unmarshal(definitionsJSON, &definitions); err != nil {
+		log.Printf("Attention: définitions des succès invalides: %v", err)
+	}
+}
+
+// All retourne la liste des succès
+func All() []*Definition {
+	return definitions
+}
+
+// indique si l'événement remplit les conditions du succès
+func (d *Definition) matches(e Event) bool {
+	if d.Trigger != e.Trigger || (d.Mode != "" && d.Mode != e.Mode) || (d.Difficulty != "" && d.Difficulty != e.Difficulty) {
+		return false
+	}
+	for _, c := range d.Conditions {
+		value, ok := e.Values[c.Metric]
+		if !ok || (c.Min != nil && value < *c.Min) || (c.Max != nil && value > *c.Max) {
+			return false
+		}
+	}
+	return true
+}
+
+// Process débloque les succès dont l'événement remplit les conditions
+//
+// e: l'événement de la partie
+// unlocked: les succès déjà débloqués et leur date, complété par Process
+// Retourne les succès débloqués par cet événement
+func Process(e Event, unlocked map[string]time.Time) []*Definition {
+	var newlyUnlocked []*Definition
+	for _, d := range definitions {
+		if _, done := unlocked[d.ID]; done || !d.matches(e) {
+			continue
+		}
+		unlocked[d.ID] = time.Now()
+		newlyUnlocked = append(newlyUnlocked, d)
+	}
+	return newlyUnlocked
+}
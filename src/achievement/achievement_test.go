@@ -0,0 +1,116 @@
+package achievement
+
+import (
+	"testing"
+	"time"
+
+	"snake-go/src/event"
+	"snake-go/src/i18n"
+)
+
+func TestDefinitions(t *testing.T) {
+	if len(All()) == 0 {
+		t.Fatal("aucun succès défini")
+	}
+	triggers := map[string]bool{FoodEaten: true, GameEnded: true}
+	seen := map[string]bool{}
+	for _, d := range All() {
+		if seen[d.ID] {
+			t.Errorf("succès %q défini deux fois", d.ID)
+		}
+		seen[d.ID] = true
+		if !triggers[d.Trigger] {
+			t.Errorf("%s: déclencheur %q inconnu", d.ID, d.Trigger)
+		}
+		if len(d.Conditions) == 0 {
+			t.Errorf("%s: aucune condition", d.ID)
+		}
+		for _, language := range i18n.Languages() {
+			i18n.SetLanguage(language.Code)
+			for _, key := range []string{"achievement." + d.ID + ".name", "achievement." + d.ID + ".description"} {
+				if i18n.T(key) == key {
+					t.Errorf("%s: clé %q manquante", language.Code, key)
+				}
+			}
+		}
+	}
+	i18n.SetLanguage(i18n.DefaultLanguage)
+}
+
+func TestProcess(t *testing.T) {
+	unlocked := map[string]time.Time{}
+
+	got := Process(Event{Trigger: FoodEaten, Mode: "Classique", Difficulty: "Normal", Values: map[string]int{"apples_total": 1, "length": 3}}, unlocked)
+	if len(got) != 1 || got[0].ID != "first_apple" {
+		t.Fatalf("la première pomme devrait débloquer first_apple, obtenu %v", got)
+	}
+	if got := Process(Event{Trigger: FoodEaten, Values: map[string]int{"apples_total": 2}}, unlocked); len(got) != 0 {
+		t.Errorf("un succès ne se débloque qu'une fois, obtenu %v", got)
+	}
+
+	// le succès sans vie perdue est limité au mode Challenge
+	flawless := map[string]int{"score": 25, "lives_lost": 0, "apples_total": 25}
+	if got := Process(Event{Trigger: FoodEaten, Mode: "Classique", Values: flawless}, unlocked); containsID(got, "challenge_flawless") {
+		t.Error("challenge_flawless ne doit pas se débloquer en mode Classique")
+	}
+	flawless["lives_lost"] = 1
+	if got := Process(Event{Trigger: FoodEaten, Mode: "Challenge", Values: flawless}, unlocked); containsID(got, "challenge_flawless") {
+		t.Error("challenge_flawless ne doit pas se débloquer après une vie perdue")
+	}
+	flawless["lives_lost"] = 0
+	if got := Process(Event{Trigger: FoodEaten, Mode: "Challenge", Values: flawless}, unlocked); !containsID(got, "challenge_flawless") {
+		t.Errorf("challenge_flawless devrait être débloqué, obtenu %v", got)
+	}
+}
+
+// Une partie Challenge se termine toujours par la perte de la dernière vie : le succès sans vie perdue
+// est testé à chaque pomme, avec les vies perdues jusqu'ici, comme le fait le jeu
+func TestChallengeFlawlessDuringGame(t *testing.T) {
+	// joue 25 pommes en Normal (2 vies), en perdant une vie avant la pomme lostAt (jamais si 0)
+	play := func(lostAt int) bool {
+		unlocked := map[string]time.Time{}
+		bus := event.NewBus()
+		score, deaths := 0, 0
+		event.Subscribe(bus, func(e event.Died) { deaths++ })
+		event.Subscribe(bus, func(e event.FoodEaten) {
+			score++
+			values := map[string]int{"score": score, "length": e.Length, "apples_total": score, "lives_lost": deaths}
+			Process(Event{Trigger: FoodEaten, Mode: "Challenge", Difficulty: "Normal", Values: values}, unlocked)
+		})
+		event.Subscribe(bus, func(e event.GameEnded) {
+			values := map[string]int{"score": e.Score, "lives_lost": deaths - 1}
+			Process(Event{Trigger: GameEnded, Mode: "Challenge", Difficulty: "Normal", Values: values}, unlocked)
+		})
+
+		for apple := 1; apple <= 25; apple++ {
+			if apple == lostAt {
+				event.Publish(bus, event.Died{Cause: "wall"})
+				event.Publish(bus, event.LifeLost{Cause: "wall", LivesLeft: 1})
+			}
+			event.Publish(bus, event.FoodEaten{Length: apple + 3})
+		}
+		event.Publish(bus, event.Died{Cause: "self"})
+		event.Publish(bus, event.GameEnded{Score: score})
+		_, ok := unlocked["challenge_flawless"]
+		return ok
+	}
+
+	if !play(0) {
+		t.Error("20 points sans perdre de vie devraient débloquer challenge_flawless, même si la dernière vie est perdue ensuite")
+	}
+	if play(10) {
+		t.Error("une vie perdue avant 20 points ne doit pas débloquer challenge_flawless")
+	}
+	if !play(22) {
+		t.Error("une vie perdue après 20 points ne doit pas empêcher challenge_flawless")
+	}
+}
+
+func containsID(definitions []*Definition, id string) bool {
+	for _, d := range definitions {
+		if d.ID == id {
+			return true
+		}
+	}
+	return false
+}
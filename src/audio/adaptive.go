@@ -0,0 +1,126 @@
+package audio
+
+import (
+	"snake-go/src/constants"
+	"snake-go/src/event"
+)
+
+// Réglages de la musique adaptative
+const (
+	tempoPerSpeedUp  = 0.03 // accélération de la musique à chaque tick gagné sur l'intervalle de départ
+	dangerClearance  = 1    // la couche d'intensité entre quand la tête a au plus ce nombre de cases libres devant un mur ou un obstacle
+	intensityFade    = 20   // durée d'apparition et de disparition de la couche d'intensité, en ticks
+	stingerDuckLevel = 0.2
+	stingerDuckTicks = 120
+)
+
+// Noms des sons synthétisés de la musique adaptative
+const (
+	intensityPreset = "intensity"
+	StingerSound    = "stinger"
+)
+
+// Etat de la musique adaptative pendant une partie
+var (
+	intensity      *track // couche jouée en boucle par-dessus la musique près des murs et des obstacles, nil si indisponible
+	startInterval  int    // intervalle de la partie au départ, auquel la musique a sa vitesse normale
+	challengeGame  bool   // une ritournelle souligne les vies perdues en mode Challenge
+	adaptiveActive bool   // la musique suit la partie en cours
+)
+
+// prépare la couche d'intensité à partir de son préréglage, jouée en boucle à la vitesse des musiques
+func loadIntensity() {
+	preset, ok := presets[intensityPreset]
+	intensity = nil
+	if backend == nil || !ok {
+		return
+	}
+	pcm := preset.Render(constants.SampleRate, 0)
+	p, err := backend.NewPlayer(intensityPreset, newTempoStream(&loopReader{pcm: pcm}))
+	if err != nil {
+		warnMissing("couche", intensityPreset, err)
+		return
+	}
+	intensity = &track{player: p, volume: constants.BackgroundVolume, step: 1.0 / intensityFade}
+}
+
+// loopReader lit des échantillons en boucle, sans fin
+type loopReader struct {
+	pcm []byte
+	pos int
+}
+
+func (l *loopReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) && len(l.pcm) > 0 {
+		copied := copy(p[n:], l.pcm[l.pos:])
+		n += copied
+		l.pos = (l.pos + copied) % len(l.pcm)
+	}
+	return n, nil
+}
+
+func (l *loopReader) Seek(offset int64, whence int) (int64, error) {
+	l.pos = 0 // seul le retour au début est utilisé
+	return 0, nil
+}
+
+// subscribeAdaptive fait suivre la partie à la musique : elle accélère avec le serpent,
+// une couche d'intensité s'ajoute près des murs et des obstacles et les vies perdues en Challenge sont soulignées
+func subscribeAdaptive(bus *event.Bus) {
+	event.Subscribe(bus, func(e event.GameStarted) {
+		startInterval, challengeGame, adaptiveActive = e.Interval, e.Mode == "Challenge", true
+		SetTempo(1)
+		if intensity != nil {
+			intensity.target = 0
+			intensity.player.Rewind()
+			intensity.player.Play()
+		}
+	})
+	event.Subscribe(bus, func(e event.SpeedUp) {
+		SetTempo(1 + float64(startInterval-e.Interval)*tempoPerSpeedUp)
+	})
+	event.Subscribe(bus, func(e event.Moved) {
+		if intensity != nil {
+			intensity.target = 0
+			if e.Clearance <= dangerClearance {
+				intensity.target = 1
+			}
+		}
+	})
+	event.Subscribe(bus, func(e event.LifeLost) {
+		if challengeGame {
+			PlaySound(StingerSound)
+			Duck(stingerDuckLevel, stingerDuckTicks)
+		}
+		if intensity != nil {
+			intensity.target = 0
+		}
+	})
+	event.Subscribe(bus, func(e event.GameEnded) { stopAdaptive() })
+}
+
+// rend à la musique sa vitesse normale et coupe la couche d'intensité
+func stopAdaptive() {
+	if !adaptiveActive {
+		return
+	}
+	adaptiveActive = false
+	SetTempo(1)
+	if intensity != nil {
+		intensity.target = 0
+	}
+}
+
+// fait avancer le fondu de la couche d'intensité et applique son volume
+func updateIntensity() {
+	t := intensity
+	if t == nil {
+		return
+	}
+	t.advance()
+	if !adaptiveActive && t.fade == 0 {
+		t.player.Pause()
+	}
+	t.player.SetVolume(t.volume * t.fade * duckGain * busGain(Music))
+}
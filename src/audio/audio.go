@@ -1,100 +1,158 @@
+// Package audio joue les bruitages et la musique du jeu
+//
+// Les sons passent par deux bus, la musique et les effets, dont le volume s'ajoute au volume général.
+// Les bruitages sont décodés une fois puis joués par un petit groupe de lecteurs pour pouvoir se superposer,
+// les musiques changent en fondu enchaîné et sont baissées un instant à la mort du serpent.
+//
+// Les sons sont joués par un Backend : la carte son avec le paquet device, ou un Recorder
+// qui enregistre les sons déclenchés sans périphérique audio.
 package audio
 
 import (
+	"io"
 	"log"
-	"os"
-
-	"github.com/hajimehoshi/ebiten/v2/audio"
-	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
 
+	"snake-go/src/audio/synth"
 	"snake-go/src/constants"
+	"snake-go/src/event"
 )
 
-// Variables globales
-var (
-	MoveSoundPlayer   *audio.Player
-	EatSoundPlayer    *audio.Player
-	LoseSoundPlayer   *audio.Player
-	BackgroundContext *audio.Context
-	BackgroundPlayer  *audio.Player
+// Noms des bruitages, chargés depuis les fichiers du thème ou synthétisés
+// Le bonus n'a pas de fichier, il est toujours synthétisé
+const (
+	MoveSound    = "move"
+	EatSound     = "eat"
+	LoseSound    = "lose"
+	PowerUpSound = "power_up"
 )
 
-// fonction pour initialiser les différents fichiers audio
-func InitAudio() {
-	BackgroundContext = audio.NewContext(constants.SampleRate)
+// Les bruitages synthétisés montent d'un demi-ton toutes les pitchLengthStep cases gagnées et à chaque accélération
+const pitchLengthStep = 4
 
-	// Chargement des bruitages
-	MoveSoundPlayer = loadAudioPlayer(BackgroundContext, "assets/move.mp3")
-	EatSoundPlayer = loadAudioPlayer(BackgroundContext, "assets/eating.mp3")
-	LoseSoundPlayer = loadAudioPlayer(BackgroundContext, "assets/lose.mp3")
+// SoundFiles donne le nom des fichiers des bruitages du serpent, comme dans le manifeste d'un thème
+type SoundFiles struct {
+	Move string
+	Eat  string
+	Lose string
+}
 
-	// Chargement de la musique de fond en boucle
-	BackgroundPlayer = loadLoopedAudioPlayer(BackgroundContext, "assets/HeatleyBros - HeatleyBros II - 06 8 Bit Adventure.mp3")
+// lecteurs des sons, nil tant que InitAudio n'a pas été appelée
+var backend Backend
 
-	// Réglage du volume pour chaque son
-	if MoveSoundPlayer != nil {
-		MoveSoundPlayer.SetVolume(constants.MoveVolume)
-	}
-	if EatSoundPlayer != nil {
-		EatSoundPlayer.SetVolume(constants.EatVolume)
-	}
-	if LoseSoundPlayer != nil {
-		LoseSoundPlayer.SetVolume(constants.LoseVolume)
-	}
-	if BackgroundPlayer != nil {
-		BackgroundPlayer.SetVolume(constants.BackgroundVolume)
-		BackgroundPlayer.Play()
-	}
-}
+// Bruitages synthétisés
+var (
+	presets     map[string]synth.Preset
+	synthesized bool                         // les bruitages du serpent sont synthétisés au lieu d'être lus dans les fichiers du thème
+	sfxRead     func(string) ([]byte, error) // fichiers du thème utilisés quand les bruitages ne sont pas synthétisés
+	sfxFiles    SoundFiles
+	length      = 1 // longueur du serpent et accélérations de la partie en cours, qui règlent la hauteur des sons
+	speedUps    int
+)
 
-// charge un fichier audio
+// fonction pour initialiser les différents fichiers audio
+// Les bruitages du serpent sont chargés ensuite par ApplyTheme
 //
-// ctx: le contexte audio utilisé pour lire les sons
-// filename: le chemin du fichier audio à charger
-// Retourne un pointeur vers un audio.Player, ou nil en cas d'erreur
-func loadAudioPlayer(ctx *audio.Context, filename string) *audio.Player {
-	file, err := os.Open(filename)
+// b: le Backend qui joue les sons
+// readAsset: la fonction qui lit les ressources du jeu, dont le fichier music.json
+// musicDir: dossier de musiques ajoutées par le joueur, ignoré s'il n'existe pas
+// presetsPath: fichier de préréglages de bruitages du joueur, ignoré s'il n'existe pas
+func InitAudio(b Backend, readAsset func(string) ([]byte, error), musicDir, presetsPath string) {
+	backend = b
+	sounds, tracks, currentMusic = map[string]*sound{}, map[string]*track{}, ""
+	duckGain, length, speedUps = 1, 1, 0
+
+	// Préréglages des bruitages synthétisés, ceux du joueur remplacent ceux du jeu
+	var err error
+	presets, err = synth.Load(presetsPath)
 	if err != nil {
-		log.Printf("Impossible de lire le fichier audio: %v", err)
-		return nil
+		log.Printf("Attention: préréglages de bruitages %s ignorés: %v", presetsPath, err)
 	}
+	synthSound(PowerUpSound, constants.PowerUpVolume)
+	synthSound(StingerSound, constants.StingerVolume)
+	loadIntensity()
+	loadCues()
 
-	d, err := mp3.DecodeWithSampleRate(constants.SampleRate, file)
-	if err != nil {
-		log.Fatal(err)
+	// Liste des musiques, chargées au moment de les jouer
+	LoadPlaylist(readAsset, musicDir)
+}
+
+// ApplyTheme recharge les bruitages depuis les fichiers d'un thème, sauf s'ils sont synthétisés
+//
+// read: la fonction qui lit les fichiers du thème
+// files: les fichiers des bruitages
+func ApplyTheme(read func(string) ([]byte, error), files SoundFiles) {
+	sfxRead, sfxFiles = read, files
+	if synthesized {
+		synthSound(MoveSound, constants.MoveVolume)
+		synthSound(EatSound, constants.EatVolume)
+		synthSound(LoseSound, constants.LoseVolume)
+		return
 	}
+	loadSound(MoveSound, read, files.Move, constants.MoveVolume)
+	loadSound(EatSound, read, files.Eat, constants.EatVolume)
+	loadSound(LoseSound, read, files.Lose, constants.LoseVolume)
+}
 
-	p, err := ctx.NewPlayer(d)
-	if err != nil {
-		log.Fatal(err)
+// SetSynthesized choisit entre les bruitages synthétisés et ceux des fichiers du thème
+func SetSynthesized(enabled bool) {
+	if enabled == synthesized {
+		return
+	}
+	synthesized = enabled
+	if sfxRead != nil {
+		ApplyTheme(sfxRead, sfxFiles)
 	}
+}
 
-	return p
+// Subscribe joue les sons du serpent en réponse aux événements de la partie
+// Les bruitages synthétisés deviennent plus aigus quand le serpent grandit ou accélère,
+// à la mort du serpent la musique est baissée le temps du bruitage
+func Subscribe(bus *event.Bus) {
+	event.Subscribe(bus, func(e event.GameStarted) { length, speedUps = 1, 0 })
+	event.Subscribe(bus, func(e event.SpeedUp) { speedUps++ })
+	event.Subscribe(bus, func(e event.LifeLost) { length = 1 })
+	event.Subscribe(bus, func(e event.Turned) { PlaySoundPitched(MoveSound, pitch()) })
+	event.Subscribe(bus, func(e event.FoodEaten) {
+		length = e.Length
+		PlaySoundPitched(EatSound, pitch())
+	})
+	event.Subscribe(bus, func(e event.ItemPicked) { PlaySound(PowerUpSound) })
+	event.Subscribe(bus, func(e event.Died) {
+		PlaySound(LoseSound)
+		Duck(duckLevel, duckTicks)
+	})
+	subscribeAdaptive(bus)
+	subscribeCues(bus)
 }
 
-// charge un fichier audio et le pase en boucle
-//
-// ctx: le contexte audio utilisé pour lire les sons
-// filename: le chemin du fichier audio à charger
-// Retourne un pointeur vers un audio.Player en boucle, ou nil en cas d'erreur
-func loadLoopedAudioPlayer(ctx *audio.Context, filename string) *audio.Player {
-	file, err := os.Open(filename)
+// hauteur des bruitages de la partie en cours, en demi-tons
+func pitch() float64 {
+	return float64((length-1)/pitchLengthStep + speedUps)
+}
+
+// lit et décode un fichier audio avec le Backend
+func decode(read func(string) ([]byte, error), filename string) (Stream, error) {
+	data, err := read(filename)
 	if err != nil {
-		log.Printf("Impossible de lire le fichier audio: %v", err)
-		return nil
+		return nil, err
 	}
+	return backend.Decode(filename, data)
+}
 
-	d, err := mp3.DecodeWithSampleRate(constants.SampleRate, file)
+// lit et décode un fichier audio en échantillons, prêts à être joués plusieurs fois
+func decodePCM(read func(string) ([]byte, error), filename string) ([]byte, error) {
+	stream, err := decode(read, filename)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-
-	loop := audio.NewInfiniteLoop(d, d.Length())
-
-	p, err := ctx.NewPlayer(loop)
+	pcm, err := io.ReadAll(stream)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
+	return pcm, nil
+}
 
-	return p
+// affiche un avertissement pour un son qui n'a pas pu être chargé
+func warnMissing(kind, filename string, err error) {
+	log.Printf("Attention: %s %s indisponible, remplacé par du silence: %v", kind, filename, err)
 }
@@ -0,0 +1,246 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"testing"
+
+	"snake-go/src/constants"
+	"snake-go/src/event"
+)
+
+// ressources factices : chaque fichier existe sauf le manifeste des musiques, remplacé par manifest
+func fakeAssets(manifest string) func(string) ([]byte, error) {
+	return func(name string) ([]byte, error) {
+		if name == musicManifest {
+			if manifest == "" {
+				return nil, errors.New("absent")
+			}
+			return []byte(manifest), nil
+		}
+		return []byte{}, nil
+	}
+}
+
+// initialise le son sans périphérique, avec les bruitages d'un thème et tous les volumes au maximum
+func setup(t *testing.T, manifest string) (*Recorder, *event.Bus) {
+	t.Helper()
+	rec := NewRecorder()
+	InitAudio(rec, fakeAssets(manifest), "", "")
+	ApplyTheme(fakeAssets(""), SoundFiles{Move: "move.mp3", Eat: "eat.mp3", Lose: "lose.mp3"})
+	for _, bus := range []Bus{Master, Music, Effects} {
+		SetVolume(bus, 1)
+	}
+	SetMuted(false)
+	SetSynthesized(false)
+
+	bus := event.NewBus()
+	Subscribe(bus)
+	return rec, bus
+}
+
+func TestEventsPlaySounds(t *testing.T) {
+	rec, bus := setup(t, "")
+
+	event.Publish(bus, event.FoodEaten{Length: 2})
+	if got := rec.Count(EatSound); got != 1 {
+		t.Errorf("manger devrait jouer le son %q une fois, joué %d fois", EatSound, got)
+	}
+	event.Publish(bus, event.Turned{Direction: 1})
+	event.Publish(bus, event.Died{Cause: "wall"})
+	event.Publish(bus, event.ItemPicked{Item: "bonus"})
+	for _, name := range []string{MoveSound, LoseSound, PowerUpSound} {
+		if rec.Count(name) != 1 {
+			t.Errorf("le son %q devrait avoir été joué, joués: %v", name, rec.Played())
+		}
+	}
+
+	// une vie perdue n'est soulignée qu'en mode Challenge
+	event.Publish(bus, event.GameStarted{Mode: "Classique", Interval: 10})
+	event.Publish(bus, event.LifeLost{Cause: "wall", LivesLeft: 1})
+	event.Publish(bus, event.GameStarted{Mode: "Challenge", Interval: 10})
+	event.Publish(bus, event.LifeLost{Cause: "wall", LivesLeft: 1})
+	if got := rec.Count(StingerSound); got != 1 {
+		t.Errorf("la ritournelle devrait être jouée une fois, jouée %d fois", got)
+	}
+}
+
+func TestMuteAndVolume(t *testing.T) {
+	rec, bus := setup(t, "")
+
+	SetMuted(true)
+	event.Publish(bus, event.FoodEaten{Length: 2})
+	if played := rec.Played(); len(played) != 0 {
+		t.Errorf("aucun son ne devrait être joué quand le son est coupé, joués: %v", played)
+	}
+
+	SetMuted(false)
+	SetVolume(Master, 0.5)
+	SetVolume(Effects, 0.5)
+	event.Publish(bus, event.FoodEaten{Length: 2})
+	played := rec.Played()
+	if len(played) != 1 {
+		t.Fatalf("un son attendu, joués: %v", played)
+	}
+	if want := 0.25 * 0.8; played[0].Volume < want-1e-9 || played[0].Volume > want+1e-9 {
+		t.Errorf("volume %v, attendu %v (général × effets × volume du son)", played[0].Volume, want)
+	}
+}
+
+func TestSoundPool(t *testing.T) {
+	rec, _ := setup(t, "")
+
+	for i := 0; i < soundPoolSize+2; i++ {
+		PlaySound(EatSound)
+	}
+	if got := rec.Count(EatSound); got != soundPoolSize+2 {
+		t.Errorf("chaque appel devrait jouer le son, joué %d fois", got)
+	}
+	if players := len(sounds[EatSound].variants[variantKey{}].players); players != soundPoolSize {
+		t.Errorf("%d lecteurs créés, attendu au plus %d", players, soundPoolSize)
+	}
+}
+
+func TestSoundPoolCutsOldest(t *testing.T) {
+	rec, _ := setup(t, "")
+	v := &soundVariant{name: "long", pcm: make([]byte, constants.SampleRate*4)} // une seconde, 60 ticks
+	wait := func(ticks int) {
+		for i := 0; i < ticks; i++ {
+			rec.Update()
+		}
+	}
+
+	// un lecteur démarré toutes les 10 ticks, puis le premier se termine et joue à nouveau
+	started := make([]Player, soundPoolSize)
+	for i := range started {
+		started[i] = v.player()
+		started[i].Play()
+		wait(10)
+	}
+	wait(60 - 10*soundPoolSize)
+	if again := v.player(); again != started[0] {
+		t.Fatalf("le lecteur libre devrait être réutilisé")
+	} else {
+		again.Rewind()
+		again.Play()
+	}
+	wait(1)
+
+	// tous jouent : le son coupé est le plus ancien, pas celui qui vient d'être relancé
+	if p := v.player(); p != started[1] {
+		t.Errorf("le lecteur le plus ancien devrait être coupé")
+	}
+}
+
+func TestPlaylistFollowsState(t *testing.T) {
+	rec, _ := setup(t, `{"tracks": [
+		{"title": "Menu", "file": "menu.mp3", "states": ["menu", "game_over"]},
+		{"title": "Partie", "file": "partie.mp3", "states": ["playing"]}
+	]}`)
+
+	PlayMusicFor(MenuMusic)
+	if track := CurrentTrack(); track == nil || track.Title != "Menu" {
+		t.Fatalf("musique du menu attendue, obtenu %v", track)
+	}
+	PlayMusicFor(PlayingMusic)
+	if track := CurrentTrack(); track == nil || track.Title != "Partie" {
+		t.Fatalf("musique de la partie attendue, obtenu %v", track)
+	}
+
+	// l'ancienne musique s'éteint en fondu
+	menu := tracks["assets/menu.mp3"]
+	for i := 0; i < MusicFade; i++ {
+		Update()
+	}
+	if menu.player.IsPlaying() || rec.Volume("assets/partie.mp3") == 0 {
+		t.Errorf("après le fondu, seule la musique de la partie devrait jouer")
+	}
+
+	// la musique du menu reprend à la fin de la partie
+	PlayMusicFor(GameOverMusic)
+	if track := CurrentTrack(); track == nil || track.Title != "Menu" {
+		t.Errorf("musique de fin de partie attendue, obtenu %v", track)
+	}
+}
+
+func TestDuckOnDeath(t *testing.T) {
+	rec, bus := setup(t, `{"tracks": [{"title": "Partie", "file": "partie.mp3"}]}`)
+	PlayMusicFor(PlayingMusic)
+	for i := 0; i < MusicFade; i++ {
+		Update()
+	}
+	normal := rec.Volume("assets/partie.mp3")
+
+	event.Publish(bus, event.Died{Cause: "wall"})
+	Update()
+	if ducked := rec.Volume("assets/partie.mp3"); ducked >= normal {
+		t.Errorf("la musique devrait baisser à la mort du serpent: %v puis %v", normal, ducked)
+	}
+	for i := 0; i < duckTicks; i++ {
+		Update()
+	}
+	if restored := rec.Volume("assets/partie.mp3"); restored != normal {
+		t.Errorf("la musique devrait retrouver son volume: %v, attendu %v", restored, normal)
+	}
+}
+
+func TestTempoFollowsSpeed(t *testing.T) {
+	_, bus := setup(t, "")
+
+	event.Publish(bus, event.GameStarted{Mode: "Classique", Interval: 10})
+	event.Publish(bus, event.SpeedUp{Interval: 8})
+	if Tempo() <= 1 {
+		t.Errorf("la musique devrait accélérer avec le serpent, tempo %v", Tempo())
+	}
+	event.Publish(bus, event.GameEnded{})
+	if Tempo() != 1 {
+		t.Errorf("la musique devrait retrouver sa vitesse à la fin de la partie, tempo %v", Tempo())
+	}
+}
+
+func TestCues(t *testing.T) {
+	rec, bus := setup(t, "")
+	defer SetCues(false)
+
+	event.Publish(bus, event.GameStarted{Mode: "Classique", Width: 20, Height: 20, Interval: 10})
+	event.Publish(bus, event.Moved{Head: image.Pt(10, 10), Food: image.Pt(2, 10), Clearance: 5})
+	if rec.Count(FoodCueSound)+rec.Count(ProximitySound) != 0 {
+		t.Errorf("aucun repère ne devrait être joué sans l'option, joués: %v", rec.Played())
+	}
+
+	SetCues(true)
+	event.Publish(bus, event.GameStarted{Mode: "Classique", Width: 20, Height: 20, Interval: 10})
+	event.Publish(bus, event.Moved{Head: image.Pt(10, 10), Food: image.Pt(2, 10), Clearance: 5})
+	if got := rec.Count(FoodCueSound); got != 1 {
+		t.Errorf("la pomme devrait être annoncée au premier déplacement, annoncée %d fois", got)
+	}
+	if _, ok := sounds[FoodCueSound].variants[variantKey{pitch: foodCuePitch / 2, pan: -3}]; !ok { // 8 cases à gauche sur 20 : -0,8
+		t.Errorf("le repère d'une pomme à gauche devrait être placé à gauche, variantes: %v", sounds[FoodCueSound].variants)
+	}
+
+	// la pomme n'est annoncée de nouveau qu'après plusieurs déplacements, la proximité à chaque déplacement
+	for i := 0; i < 3; i++ {
+		event.Publish(bus, event.Moved{Head: image.Pt(1, 10), Food: image.Pt(2, 10), Clearance: 1})
+	}
+	if got := rec.Count(FoodCueSound); got != 1 {
+		t.Errorf("la pomme ne devrait pas être annoncée à chaque déplacement, annoncée %d fois", got)
+	}
+	if got := rec.Count(ProximitySound); got != 3 {
+		t.Errorf("le son de proximité devrait être joué à chaque déplacement près d'un mur, joué %d fois", got)
+	}
+}
+
+func TestPanPCM(t *testing.T) {
+	pcm := []byte{0x00, 0x40, 0x00, 0x40} // une image stéréo, 16384 des deux côtés
+	left := panPCM(pcm, -1)
+	if r := int16(binary.LittleEndian.Uint16(left[2:])); r != 0 {
+		t.Errorf("un son à gauche ne devrait rien jouer à droite, %d", r)
+	}
+	if l := int16(binary.LittleEndian.Uint16(left[0:])); l != 0x4000 {
+		t.Errorf("un son à gauche devrait garder son volume à gauche, %d", l)
+	}
+	if centered := panPCM(pcm, 0); &centered[0] != &pcm[0] {
+		t.Errorf("un son au centre ne devrait pas être copié")
+	}
+}
@@ -0,0 +1,34 @@
+package audio
+
+import (
+	"io"
+)
+
+// Backend crée les lecteurs qui jouent les sons, sur la carte son ou sans périphérique
+// L'implémentation ebiten est dans le paquet device, Recorder joue les sons sans les entendre
+type Backend interface {
+	// Decode décode un fichier audio en échantillons 16 bits stéréo à constants.SampleRate
+	Decode(filename string, data []byte) (Stream, error)
+	// NewPlayer crée un lecteur qui lit un flux au fur et à mesure
+	NewPlayer(name string, src io.ReadSeeker) (Player, error)
+	// NewPlayerFromBytes crée un lecteur d'échantillons déjà décodés
+	NewPlayerFromBytes(name string, pcm []byte) Player
+	// Update est appelée à chaque tick par audio.Update
+	Update()
+}
+
+// Stream est un fichier audio décodé, quel que soit son format
+type Stream interface {
+	io.ReadSeeker
+	Length() int64
+}
+
+// Player joue un son, les méthodes sont celles du lecteur d'ebiten
+type Player interface {
+	Play()
+	Pause()
+	Rewind() error
+	IsPlaying() bool
+	SetVolume(volume float64)
+	Close() error
+}
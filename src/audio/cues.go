@@ -0,0 +1,66 @@
+package audio
+
+import (
+	"snake-go/src/constants"
+	"snake-go/src/event"
+)
+
+// Noms des repères sonores de l'accessibilité, toujours synthétisés
+const (
+	FocusSound     = "focus"     // le focus des menus change de widget
+	FoodCueSound   = "food_cue"  // la pomme, placée à gauche ou à droite de la tête dans l'espace stéréo
+	ProximitySound = "proximity" // la tête approche d'un mur ou d'un obstacle
+)
+
+// Réglages des repères sonores
+const (
+	foodCueInterval = 8  // déplacements entre deux repères de la pomme
+	foodCuePitch    = 12 // écart de hauteur entre une pomme tout en bas et une pomme tout en haut du plateau, en demi-tons
+	proximityRange  = 3  // le son de proximité est joué quand la tête a au plus ce nombre de cases libres
+	proximityPitch  = 4  // le son de proximité monte de ce nombre de demi-tons à chaque case libre en moins
+)
+
+// Etat des repères sonores pendant une partie
+var (
+	cuesEnabled bool
+	boardWidth  int // dimensions du plateau de la partie en cours, en cellules
+	boardHeight int
+	cueMoves    int // déplacements depuis le dernier repère de la pomme
+)
+
+// prépare les sons des repères, synthétisés même quand les bruitages du serpent viennent du thème
+func loadCues() {
+	synthSound(FocusSound, constants.CueVolume)
+	synthSound(FoodCueSound, constants.CueVolume)
+	synthSound(ProximitySound, constants.CueVolume)
+}
+
+// SetCues active les repères sonores de la partie : direction de la pomme et proximité des murs et des obstacles
+func SetCues(enabled bool) {
+	cuesEnabled = enabled
+}
+
+// subscribeCues fait entendre où se trouve la pomme par rapport à la tête, à gauche ou à droite et plus aigu
+// quand elle est plus haut, et un son de plus en plus aigu quand la tête approche d'un mur ou d'un obstacle
+func subscribeCues(bus *event.Bus) {
+	event.Subscribe(bus, func(e event.GameStarted) {
+		boardWidth, boardHeight = e.Width, e.Height
+		cueMoves = foodCueInterval - 1 // la pomme est annoncée dès le premier déplacement
+	})
+	event.Subscribe(bus, func(e event.LifeLost) { cueMoves = foodCueInterval - 1 })
+	event.Subscribe(bus, func(e event.FoodEaten) { cueMoves = foodCueInterval - 1 })
+	event.Subscribe(bus, func(e event.Moved) {
+		if !cuesEnabled || boardWidth == 0 || boardHeight == 0 {
+			return
+		}
+		if cueMoves++; cueMoves >= foodCueInterval {
+			cueMoves = 0
+			pan := float64(e.Food.X-e.Head.X) / float64(boardWidth) * 2
+			height := float64(e.Head.Y-e.Food.Y)/float64(boardHeight)*foodCuePitch/2 + foodCuePitch/2
+			PlaySoundPanned(FoodCueSound, height, pan)
+		}
+		if e.Clearance <= proximityRange {
+			PlaySoundPitched(ProximitySound, float64((proximityRange-max(e.Clearance, 0))*proximityPitch))
+		}
+	})
+}
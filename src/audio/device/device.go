@@ -0,0 +1,54 @@
+// Package device joue les sons du jeu sur la carte son, avec le contexte audio d'ebiten
+package device
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	ebitenaudio "github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+
+	"snake-go/src/audio"
+)
+
+// Backend joue les sons avec le contexte audio d'ebiten
+type Backend struct {
+	context    *ebitenaudio.Context
+	sampleRate int
+}
+
+// New ouvre le contexte audio d'ebiten, il ne peut en exister qu'un pendant toute l'exécution du jeu
+//
+// sampleRate: la fréquence d'échantillonnage des sons
+func New(sampleRate int) *Backend {
+	return &Backend{context: ebitenaudio.NewContext(sampleRate), sampleRate: sampleRate}
+}
+
+// Decode décode un fichier mp3, ogg ou wav selon son extension
+func (b *Backend) Decode(filename string, data []byte) (audio.Stream, error) {
+	r := bytes.NewReader(data)
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".mp3":
+		return mp3.DecodeWithSampleRate(b.sampleRate, r)
+	case ".ogg":
+		return vorbis.DecodeWithSampleRate(b.sampleRate, r)
+	case ".wav":
+		return wav.DecodeWithSampleRate(b.sampleRate, r)
+	}
+	return nil, fmt.Errorf("format audio non reconnu")
+}
+
+func (b *Backend) NewPlayer(name string, src io.ReadSeeker) (audio.Player, error) {
+	return b.context.NewPlayer(src)
+}
+
+func (b *Backend) NewPlayerFromBytes(name string, pcm []byte) audio.Player {
+	return b.context.NewPlayerFromBytes(pcm)
+}
+
+func (b *Backend) Update() {}
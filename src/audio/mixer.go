@@ -0,0 +1,77 @@
+package audio
+
+// Bus désigne un volume réglable séparément
+type Bus int
+
+const (
+	Master  Bus = iota // volume général, appliqué à tous les sons
+	Music              // musiques
+	Effects            // bruitages
+)
+
+// Baisse de la musique à la mort du serpent : niveau atteint et durée du retour au volume normal, en ticks
+const (
+	duckLevel = 0.3
+	duckTicks = 90
+)
+
+// Etat du mélangeur
+var (
+	volumes     = [...]float64{Master: 1, Music: 1, Effects: 1}
+	muted       bool
+	duckGain    = 1.0 // gain appliqué à la musique, inférieur à 1 pendant une baisse
+	duckRecover float64
+)
+
+// SetVolume règle le volume d'un bus, entre 0 et 1
+func SetVolume(bus Bus, volume float64) {
+	volumes[bus] = min(max(volume, 0), 1)
+	updateMusic()
+}
+
+// Volume retourne le volume d'un bus
+func Volume(bus Bus) float64 {
+	return volumes[bus]
+}
+
+// SetMuted coupe ou rétablit tous les sons
+func SetMuted(mute bool) {
+	muted = mute
+	updateMusic()
+}
+
+// Muted indique si les sons sont coupés
+func Muted() bool {
+	return muted
+}
+
+// gain d'un bus, volume général compris
+func busGain(bus Bus) float64 {
+	if muted {
+		return 0
+	}
+	return volumes[Master] * volumes[bus]
+}
+
+// Duck baisse la musique puis la ramène progressivement à son volume
+//
+// level: le gain de la musique juste après l'appel, entre 0 et 1
+// ticks: la durée du retour au volume normal
+func Duck(level float64, ticks int) {
+	duckGain = min(duckGain, level)
+	duckRecover = (1 - duckGain) / float64(max(ticks, 1))
+}
+
+// Update fait avancer les fondus et la baisse de la musique, à appeler à chaque tick
+func Update() {
+	if backend != nil {
+		backend.Update()
+	}
+	if duckGain < 1 {
+		duckGain = min(duckGain+duckRecover, 1)
+	}
+	advanceFades()
+	updateMusic()
+	updateIntensity()
+	updatePlaylist()
+}
@@ -0,0 +1,132 @@
+package audio
+
+// MusicFade est la durée par défaut d'un fondu enchaîné entre deux musiques, en ticks
+const MusicFade = 60
+
+// track est une musique chargée et son fondu en cours
+type track struct {
+	player Player
+	volume float64 // volume propre de la musique
+	fade   float64 // gain du fondu, entre 0 et 1
+	target float64 // gain vers lequel le fondu avance
+	step   float64 // variation du gain par tick
+}
+
+// Musiques chargées, par nom
+var (
+	tracks       = map[string]*track{}
+	currentMusic string
+)
+
+// LoadMusic charge une musique, jouée une fois à chaque appel de PlayMusic à la vitesse réglée par SetTempo, un fichier absent ou invalide est remplacé par du silence
+//
+// name: le nom de la musique, utilisé par PlayMusic
+// read: la fonction qui lit le fichier
+// filename: le nom du fichier mp3, ogg ou wav
+// volume: le volume propre de la musique, multiplié par celui du bus de la musique
+// Retourne faux si la musique n'a pas pu être chargée
+func LoadMusic(name string, read func(string) ([]byte, error), filename string, volume float64) bool {
+	if backend == nil {
+		return false
+	}
+	d, err := decode(read, filename)
+	if err != nil {
+		warnMissing("musique", filename, err)
+		return false
+	}
+	p, err := backend.NewPlayer(name, newTempoStream(d))
+	if err != nil {
+		warnMissing("musique", filename, err)
+		return false
+	}
+	if old := tracks[name]; old != nil {
+		old.player.Close()
+	}
+	tracks[name] = &track{player: p, volume: volume}
+	return true
+}
+
+// PlayMusic passe à une musique en fondu enchaîné, rien ne change si elle est déjà jouée
+//
+// name: le nom de la musique, une musique inconnue arrête simplement la musique en cours
+// fade: la durée du fondu en ticks, 0 pour changer immédiatement
+func PlayMusic(name string, fade int) {
+	if name == currentMusic {
+		if t := tracks[name]; t != nil && t.player.IsPlaying() && t.target == 1 {
+			return
+		}
+	}
+	StopMusic(fade)
+	currentMusic = name
+	t := tracks[name]
+	if t == nil {
+		return
+	}
+	if !t.player.IsPlaying() {
+		t.player.Rewind()
+		t.fade = 0
+	}
+	t.fadeTo(1, fade)
+	t.player.Play()
+	updateMusic()
+}
+
+// StopMusic arrête la musique en cours en fondu
+//
+// fade: la durée du fondu en ticks, 0 pour arrêter immédiatement
+func StopMusic(fade int) {
+	if t := tracks[currentMusic]; t != nil {
+		t.fadeTo(0, fade)
+	}
+	currentMusic = ""
+	updateMusic()
+}
+
+// CurrentMusic retourne le nom de la musique jouée, "" s'il n'y en a pas
+func CurrentMusic() string {
+	return currentMusic
+}
+
+// indique si la musique en cours est arrivée à sa fin
+func musicEnded() bool {
+	t := tracks[currentMusic]
+	return t != nil && t.target == 1 && !t.player.IsPlaying()
+}
+
+// lance un fondu vers le gain donné
+func (t *track) fadeTo(target float64, ticks int) {
+	t.target = target
+	if ticks <= 0 {
+		t.fade = target
+		return
+	}
+	t.step = 1 / float64(ticks)
+}
+
+// fait avancer d'un tick le fondu de chaque musique
+func advanceFades() {
+	for _, t := range tracks {
+		t.advance()
+	}
+}
+
+// fait avancer le fondu d'un tick vers son gain cible
+func (t *track) advance() {
+	switch {
+	case t.fade < t.target:
+		t.fade = min(t.fade+t.step, t.target)
+	case t.fade > t.target:
+		t.fade = max(t.fade-t.step, t.target)
+	}
+}
+
+// applique le volume de chaque musique, une musique éteinte par son fondu est mise en pause
+func updateMusic() {
+	for _, t := range tracks {
+		if t.fade == 0 && t.target == 0 {
+			t.player.Pause()
+			continue
+		}
+		t.player.SetVolume(t.volume * t.fade * duckGain * busGain(Music))
+	}
+}
@@ -0,0 +1,257 @@
+package audio
+
+import (
+	"encoding/json"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"snake-go/src/constants"
+)
+
+// MusicState désigne un moment du jeu qui a ses propres musiques
+type MusicState string
+
+const (
+	MenuMusic     MusicState = "menu"
+	PlayingMusic  MusicState = "playing"
+	GameOverMusic MusicState = "game_over"
+)
+
+// moments du jeu, dans l'ordre, utilisés aussi comme noms des sous-dossiers du dossier de musiques
+var musicStates = []MusicState{MenuMusic, PlayingMusic, GameOverMusic}
+
+// fichier des ressources qui décrit les musiques livrées avec le jeu
+const musicManifest = "music.json"
+
+// extensions des fichiers reconnus comme des musiques
+var musicExtensions = []string{".mp3", ".ogg", ".wav"}
+
+// Track décrit une musique de la liste de lecture
+type Track struct {
+	Title  string       `json:"title"`
+	Artist string       `json:"artist"`
+	File   string       `json:"file"`
+	States []MusicState `json:"states"` // moments où la musique est jouée, tous si vide
+	id     string       // nom sous lequel la musique est chargée
+	read   func(string) ([]byte, error)
+	broken bool // la musique n'a pas pu être chargée, elle est sautée
+}
+
+// Label retourne le texte qui présente la musique : l'artiste et le titre
+func (t *Track) Label() string {
+	if t.Artist == "" {
+		return t.Title
+	}
+	return t.Artist + " - " + t.Title
+}
+
+// indique si la musique est jouée au moment donné
+func (t *Track) playsIn(state MusicState) bool {
+	return len(t.States) == 0 || slices.Contains(t.States, state)
+}
+
+// charge la musique si ce n'est pas déjà fait
+// Retourne faux si elle ne peut pas être jouée
+func (t *Track) load() bool {
+	if t.broken {
+		return false
+	}
+	if tracks[t.id] != nil {
+		return true
+	}
+	t.broken = !LoadMusic(t.id, t.read, t.File, constants.BackgroundVolume)
+	return !t.broken
+}
+
+// playlist est la liste de lecture d'un moment du jeu
+type playlist struct {
+	tracks []*Track
+	order  []int // ordre de lecture des musiques, mélangé si la lecture aléatoire est activée
+	next   int   // position dans order de la prochaine musique
+}
+
+// Etat de la lecture
+var (
+	playlists  = map[MusicState]*playlist{}
+	musicState MusicState
+	playing    *Track // musique en cours, nil s'il n'y en a pas
+	shuffle    = true
+)
+
+// LoadPlaylist prépare les listes de lecture à partir des musiques du jeu et de celles du joueur
+// Les musiques du jeu sont décrites dans music.json, les fichiers du dossier du joueur sont joués à tous les moments
+// sauf ceux rangés dans un sous-dossier menu, playing ou game_over
+//
+// readAsset: la fonction qui lit music.json et les musiques du jeu
+// dir: le dossier des musiques du joueur, ignoré s'il n'existe pas
+func LoadPlaylist(readAsset func(string) ([]byte, error), dir string) {
+	all := append(manifestTracks(readAsset), discoverTracks(dir)...)
+	if len(all) == 0 {
+		log.Printf("Attention: aucune musique trouvée, le jeu sera joué sans musique")
+	}
+
+	playlists = map[MusicState]*playlist{}
+	for _, state := range musicStates {
+		p := &playlist{}
+		for _, t := range all {
+			if t.playsIn(state) {
+				p.tracks = append(p.tracks, t)
+			}
+		}
+		playlists[state] = p
+	}
+	musicState, playing = "", nil
+}
+
+// musiques décrites par le manifeste des ressources
+func manifestTracks(readAsset func(string) ([]byte, error)) []*Track {
+	data, err := readAsset(musicManifest)
+	if err != nil {
+		log.Printf("Attention: liste des musiques %s introuvable: %v", musicManifest, err)
+		return nil
+	}
+	var manifest struct {
+		Tracks []*Track `json:"tracks"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		log.Printf("Attention: liste des musiques %s invalide: %v", musicManifest, err)
+		return nil
+	}
+	for _, t := range manifest.Tracks {
+		t.id = "assets/" + t.File
+		t.read = readAsset
+		if t.Title == "" {
+			t.Title = titleFromFile(t.File)
+		}
+	}
+	return manifest.Tracks
+}
+
+// musiques trouvées dans le dossier du joueur et ses sous-dossiers par moment du jeu
+func discoverTracks(dir string) []*Track {
+	if dir == "" {
+		return nil
+	}
+	found := findTracks(dir, nil)
+	for _, state := range musicStates {
+		found = append(found, findTracks(filepath.Join(dir, string(state)), []MusicState{state})...)
+	}
+	return found
+}
+
+// musiques d'un dossier, triées par nom de fichier
+func findTracks(dir string, states []MusicState) []*Track {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var found []*Track
+	for _, entry := range entries {
+		if entry.IsDir() || !slices.Contains(musicExtensions, strings.ToLower(filepath.Ext(entry.Name()))) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		found = append(found, &Track{
+			Title:  titleFromFile(entry.Name()),
+			File:   path,
+			States: states,
+			id:     path,
+			read:   os.ReadFile,
+		})
+	}
+	return found
+}
+
+// titre d'une musique déduit de son nom de fichier
+func titleFromFile(name string) string {
+	return strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+}
+
+// SetShuffle active ou désactive la lecture aléatoire, appliquée à partir de la prochaine musique
+func SetShuffle(enabled bool) {
+	shuffle = enabled
+	for _, p := range playlists {
+		p.order = nil
+	}
+}
+
+// PlayMusicFor joue les musiques d'un moment du jeu, à appeler à chaque changement d'état
+// La musique en cours continue si elle fait aussi partie de la liste de ce moment
+func PlayMusicFor(state MusicState) {
+	if state == musicState {
+		return
+	}
+	musicState = state
+	if state != PlayingMusic {
+		stopAdaptive()
+	}
+	if p := playlists[state]; p != nil && playing != nil && slices.Contains(p.tracks, playing) {
+		return
+	}
+	playNext(MusicFade)
+}
+
+// CurrentTrack retourne la musique en cours, nil s'il n'y en a pas
+func CurrentTrack() *Track {
+	return playing
+}
+
+// passe à la musique suivante de la liste du moment en cours
+func playNext(fade int) {
+	var t *Track
+	if p := playlists[musicState]; p != nil {
+		t = p.pick()
+	}
+	playing = t
+	if t == nil {
+		StopMusic(fade)
+		return
+	}
+	PlayMusic(t.id, fade)
+}
+
+// enchaîne la musique suivante quand la musique en cours est terminée
+func updatePlaylist() {
+	if playing != nil && musicEnded() {
+		playNext(0)
+	}
+}
+
+// choisit la prochaine musique jouable de la liste, nil s'il n'y en a aucune
+func (p *playlist) pick() *Track {
+	for range p.tracks {
+		if p.next >= len(p.order) {
+			p.reorder()
+		}
+		t := p.tracks[p.order[p.next]]
+		p.next++
+		if t.load() {
+			return t
+		}
+	}
+	return nil
+}
+
+// prépare un nouveau tour de la liste, en évitant de rejouer tout de suite la musique en cours
+func (p *playlist) reorder() {
+	p.order = make([]int, len(p.tracks))
+	for i := range p.order {
+		p.order[i] = i
+	}
+	p.next = 0
+	if !shuffle || len(p.order) < 2 {
+		return
+	}
+	rand.Shuffle(len(p.order), func(i, j int) { p.order[i], p.order[j] = p.order[j], p.order[i] })
+	if p.tracks[p.order[0]] == playing {
+		last := len(p.order) - 1
+		p.order[0], p.order[last] = p.order[last], p.order[0]
+	}
+}
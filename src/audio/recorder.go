@@ -0,0 +1,141 @@
+package audio
+
+import (
+	"bytes"
+	"io"
+
+	"snake-go/src/constants"
+)
+
+// Réglages des sons joués sans périphérique
+const (
+	recorderTPS     = 60                            // ticks par seconde, ceux d'ebiten par défaut
+	recorderDecoded = constants.SampleRate * 4 / 10 // un fichier décodé devient 0,1 s de silence
+)
+
+// Played est un son déclenché, enregistré par un Recorder
+type Played struct {
+	Tick   int // nombre d'appels à audio.Update avant le son
+	Name   string
+	Volume float64
+}
+
+// Recorder est un Backend sans périphérique audio, pour les tests, les serveurs et les robots
+// Les fichiers ne sont pas décodés et les sons ne sont pas entendus, mais chaque son déclenché est enregistré
+type Recorder struct {
+	tick     int
+	played   []Played
+	volumes  map[string]float64
+	disabled bool
+}
+
+// NewRecorder crée un Backend qui enregistre les sons déclenchés
+func NewRecorder() *Recorder {
+	return &Recorder{volumes: map[string]float64{}}
+}
+
+// NewNull crée un Backend silencieux qui n'enregistre rien, pour jouer longtemps sans carte son
+func NewNull() *Recorder {
+	return &Recorder{volumes: map[string]float64{}, disabled: true}
+}
+
+// Played retourne les sons déclenchés, dans l'ordre
+func (r *Recorder) Played() []Played {
+	return append([]Played(nil), r.played...)
+}
+
+// Count retourne le nombre de fois où un son a été déclenché
+func (r *Recorder) Count(name string) int {
+	count := 0
+	for _, p := range r.played {
+		if p.Name == name {
+			count++
+		}
+	}
+	return count
+}
+
+// Volume retourne le dernier volume donné à un lecteur du son, 0 s'il n'a jamais été réglé
+func (r *Recorder) Volume(name string) float64 {
+	return r.volumes[name]
+}
+
+// Reset oublie les sons déjà enregistrés
+func (r *Recorder) Reset() {
+	r.played = nil
+}
+
+func (r *Recorder) Decode(filename string, data []byte) (Stream, error) {
+	return silence{bytes.NewReader(make([]byte, recorderDecoded))}, nil
+}
+
+// silence remplace les fichiers que le Recorder ne décode pas
+type silence struct {
+	*bytes.Reader
+}
+
+func (s silence) Length() int64 {
+	return s.Size()
+}
+
+func (r *Recorder) NewPlayer(name string, src io.ReadSeeker) (Player, error) {
+	return &recordedPlayer{recorder: r, name: name, duration: -1}, nil
+}
+
+func (r *Recorder) NewPlayerFromBytes(name string, pcm []byte) Player {
+	duration := max(len(pcm)/4*recorderTPS/constants.SampleRate, 1)
+	return &recordedPlayer{recorder: r, name: name, duration: duration}
+}
+
+func (r *Recorder) Update() {
+	r.tick++
+}
+
+// recordedPlayer simule la lecture d'un son au rythme des ticks
+type recordedPlayer struct {
+	recorder *Recorder
+	name     string
+	duration int // durée en ticks, négative pour un flux sans fin connue
+	position int // ticks déjà joués à la dernière pause
+	since    int // tick du début de la lecture en cours
+	playing  bool
+}
+
+func (p *recordedPlayer) elapsed() int {
+	if !p.playing {
+		return p.position
+	}
+	return p.position + p.recorder.tick - p.since
+}
+
+func (p *recordedPlayer) Play() {
+	if !p.playing {
+		p.playing, p.since = true, p.recorder.tick
+	}
+	if !p.recorder.disabled {
+		p.recorder.played = append(p.recorder.played, Played{Tick: p.recorder.tick, Name: p.name, Volume: p.recorder.volumes[p.name]})
+	}
+}
+
+func (p *recordedPlayer) Pause() {
+	p.position = p.elapsed()
+	p.playing = false
+}
+
+func (p *recordedPlayer) Rewind() error {
+	p.position, p.since = 0, p.recorder.tick
+	return nil
+}
+
+func (p *recordedPlayer) IsPlaying() bool {
+	return p.playing && (p.duration < 0 || p.elapsed() < p.duration)
+}
+
+func (p *recordedPlayer) SetVolume(volume float64) {
+	p.recorder.volumes[p.name] = volume
+}
+
+func (p *recordedPlayer) Close() error {
+	p.playing = false
+	return nil
+}
@@ -0,0 +1,174 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+
+	"snake-go/src/audio/synth"
+	"snake-go/src/constants"
+)
+
+// nombre maximal de lecteurs d'un même bruitage, au-delà le plus ancien est réutilisé
+const soundPoolSize = 4
+
+// écart de hauteur maximal d'un bruitage synthétisé, en demi-tons
+const maxPitch = 12
+
+// nombre de positions de chaque côté du centre entre lesquelles un bruitage est placé dans l'espace stéréo
+const panSteps = 4
+
+// sound est un bruitage, lu depuis un fichier ou synthétisé
+type sound struct {
+	name     string
+	volume   float64
+	pcm      []byte                       // échantillons décodés d'un fichier, nil pour un son synthétisé
+	preset   *synth.Preset                // préréglage d'un son synthétisé, nil pour un fichier
+	variants map[variantKey]*soundVariant // échantillons par hauteur et position stéréo, rendus au premier usage
+}
+
+// variantKey repère une variante d'un bruitage
+type variantKey struct {
+	pitch int // hauteur en demi-tons, toujours 0 pour un fichier
+	pan   int // position stéréo, de -panSteps (à gauche) à panSteps (à droite)
+}
+
+// soundVariant est un bruitage décodé à une hauteur et les lecteurs qui le jouent
+type soundVariant struct {
+	pcm     []byte
+	players []Player
+	name    string
+}
+
+// Bruitages chargés, par nom
+var sounds = map[string]*sound{}
+
+// charge un bruitage, un fichier absent ou invalide est remplacé par du silence
+//
+// name: le nom du bruitage (MoveSound, EatSound...)
+// read: la fonction qui lit le fichier (ressources du jeu ou fichiers d'un thème)
+// filename: le nom du fichier audio
+// volume: le volume propre du bruitage, multiplié par celui du bus des effets
+func loadSound(name string, read func(string) ([]byte, error), filename string, volume float64) {
+	closeSound(name)
+	if backend == nil {
+		return
+	}
+
+	pcm, err := decodePCM(read, filename)
+	if err != nil {
+		warnMissing("son", filename, err)
+		return
+	}
+	sounds[name] = &sound{name: name, volume: volume, pcm: pcm, variants: map[variantKey]*soundVariant{}}
+}
+
+// prépare un bruitage synthétisé, rendu à chaque nouvelle hauteur demandée
+// Un préréglage absent est remplacé par du silence
+func synthSound(name string, volume float64) {
+	closeSound(name)
+	preset, ok := presets[name]
+	if backend == nil || !ok {
+		return
+	}
+	sounds[name] = &sound{name: name, volume: volume, preset: &preset, variants: map[variantKey]*soundVariant{}}
+}
+
+// libère les lecteurs d'un bruitage avant de le remplacer
+func closeSound(name string) {
+	if old := sounds[name]; old != nil {
+		for _, v := range old.variants {
+			for _, p := range v.players {
+				p.Close()
+			}
+		}
+		delete(sounds, name)
+	}
+}
+
+// PlaySound joue un bruitage depuis le début, un bruitage inconnu est ignoré
+// Plusieurs lectures du même bruitage peuvent se superposer
+func PlaySound(name string) {
+	PlaySoundPitched(name, 0)
+}
+
+// PlaySoundPitched joue un bruitage plus aigu, seuls les bruitages synthétisés changent de hauteur
+//
+// semitones: l'écart de hauteur en demi-tons, limité entre 0 et 12
+func PlaySoundPitched(name string, semitones float64) {
+	PlaySoundPanned(name, semitones, 0)
+}
+
+// PlaySoundPanned joue un bruitage placé à gauche ou à droite dans l'espace stéréo
+//
+// semitones: l'écart de hauteur en demi-tons, comme pour PlaySoundPitched
+// pan: la position du son, de -1 (à gauche) à 1 (à droite), 0 au centre
+func PlaySoundPanned(name string, semitones, pan float64) {
+	s := sounds[name]
+	if s == nil || muted {
+		return
+	}
+	p := s.variant(semitones, pan).player()
+	p.SetVolume(s.volume * busGain(Effects))
+	p.Rewind()
+	p.Play()
+}
+
+// échantillons du bruitage à la hauteur demandée, arrondie au demi-ton, et à la position stéréo demandée,
+// arrondie à la plus proche des panSteps positions de chaque côté, rendus au premier appel
+func (s *sound) variant(semitones, pan float64) *soundVariant {
+	key := variantKey{pan: int(math.Round(min(max(pan, -1), 1) * panSteps))}
+	if s.preset != nil {
+		key.pitch = int(math.Round(min(max(semitones, 0), maxPitch)))
+	}
+	v := s.variants[key]
+	if v == nil {
+		pcm := s.pcm
+		if s.preset != nil {
+			pcm = s.preset.Render(constants.SampleRate, float64(key.pitch))
+		}
+		v = &soundVariant{pcm: panPCM(pcm, float64(key.pan)/panSteps), name: s.name}
+		s.variants[key] = v
+	}
+	return v
+}
+
+// place des échantillons stéréo 16 bits dans l'espace : le côté opposé à pan est atténué
+// Les échantillons d'origine sont retournés tels quels pour un son au centre
+func panPCM(pcm []byte, pan float64) []byte {
+	if pan == 0 {
+		return pcm
+	}
+	left, right := min(1-pan, 1), min(1+pan, 1)
+	out := make([]byte, len(pcm))
+	for i := 0; i+3 < len(pcm); i += 4 {
+		l := int16(binary.LittleEndian.Uint16(pcm[i:]))
+		r := int16(binary.LittleEndian.Uint16(pcm[i+2:]))
+		binary.LittleEndian.PutUint16(out[i:], uint16(int16(float64(l)*left)))
+		binary.LittleEndian.PutUint16(out[i+2:], uint16(int16(float64(r)*right)))
+	}
+	return out
+}
+
+// lecteur libre du bruitage, créé si besoin, ou le plus ancien si tous jouent déjà
+// Les lecteurs restent rangés du plus ancien au plus récent : celui qui est donné passe à la fin
+func (v *soundVariant) player() Player {
+	for i, p := range v.players {
+		if !p.IsPlaying() {
+			return v.use(i)
+		}
+	}
+	if len(v.players) < soundPoolSize {
+		p := backend.NewPlayerFromBytes(v.name, v.pcm)
+		v.players = append(v.players, p)
+		return p
+	}
+	return v.use(0)
+}
+
+// passe le lecteur i à la fin des lecteurs, le plus récent, et le retourne
+func (v *soundVariant) use(i int) Player {
+	p := v.players[i]
+	copy(v.players[i:], v.players[i+1:])
+	v.players[len(v.players)-1] = p
+	return p
+}
@@ -0,0 +1,185 @@
+// Package synth fabrique des bruitages de console 8 bits : oscillateurs carré, triangle et bruit,
+// enveloppes et glissements de hauteur
+//
+// Les sons sont décrits par des préréglages écrits en JSON et rendus en échantillons 16 bits stéréo,
+// le format attendu par le contexte audio d'ebiten.
+package synth
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+)
+
+// Formes d'onde des oscillateurs
+type Waveform string
+
+const (
+	Square   Waveform = "square"
+	Triangle Waveform = "triangle"
+	Noise    Waveform = "noise"
+)
+
+//go:embed presets.json
+var presetsJSON []byte
+
+// Envelope décrit l'évolution du volume d'un son, les durées sont en secondes
+type Envelope struct {
+	Attack  float64 `json:"attack"`  // montée jusqu'au volume maximal
+	Decay   float64 `json:"decay"`   // descente jusqu'au niveau de maintien
+	Sustain float64 `json:"sustain"` // niveau de maintien, entre 0 et 1
+	Release float64 `json:"release"` // extinction après la durée du son
+}
+
+// Preset décrit un bruitage
+type Preset struct {
+	Wave      Waveform  `json:"wave"`
+	Frequency float64   `json:"frequency"` // fréquence de départ en Hz, cadence du générateur pour le bruit
+	Sweep     float64   `json:"sweep"`     // glissement de hauteur sur la durée du son, en demi-tons
+	Arpeggio  []float64 `json:"arpeggio"`  // notes jouées à la suite, en demi-tons au-dessus de Frequency
+	Duty      float64   `json:"duty"`      // rapport cyclique du carré, 0.5 si absent
+	Duration  float64   `json:"duration"`  // durée en secondes, extinction non comprise
+	Volume    float64   `json:"volume"`    // entre 0 et 1, 1 si absent
+	Envelope  Envelope  `json:"envelope"`
+}
+
+// Defaults retourne les préréglages livrés avec le jeu, par nom de son (move, eat, lose, power_up)
+func Defaults() map[string]Preset {
+	presets, err := Parse(presetsJSON)
+	if err != nil {
+		panic(fmt.Sprintf("préréglages intégrés invalides: %v", err))
+	}
+	return presets
+}
+
+// Load retourne les préréglages intégrés, remplacés par ceux du fichier donné
+//
+// path: le fichier de préréglages du joueur, ignoré s'il n'existe pas
+// Retourne les préréglages intégrés et une erreur si le fichier est invalide
+func Load(path string) (map[string]Preset, error) {
+	presets := Defaults()
+	if path == "" {
+		return presets, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return presets, nil
+		}
+		return presets, err
+	}
+	custom, err := Parse(data)
+	if err != nil {
+		return presets, err
+	}
+	for name, p := range custom {
+		presets[name] = p
+	}
+	return presets, nil
+}
+
+// Parse lit des préréglages écrits en JSON, un objet par nom de son
+// Les valeurs absentes prennent leur valeur par défaut
+func Parse(data []byte) (map[string]Preset, error) {
+	var presets map[string]Preset
+	if err := json.Unmarshal(data, &presets); err != nil {
+		return nil, err
+	}
+	for name, p := range presets {
+		switch p.Wave {
+		case Square, Triangle, Noise:
+		default:
+			return nil, fmt.Errorf("%s: forme d'onde %q inconnue", name, p.Wave)
+		}
+		if p.Frequency <= 0 || p.Duration <= 0 {
+			return nil, fmt.Errorf("%s: la fréquence et la durée doivent être positives", name)
+		}
+		if p.Duty <= 0 || p.Duty >= 1 {
+			p.Duty = 0.5
+		}
+		if p.Volume <= 0 {
+			p.Volume = 1
+		}
+		p.Envelope.Sustain = min(max(p.Envelope.Sustain, 0), 1)
+		presets[name] = p
+	}
+	return presets, nil
+}
+
+// Render rend le son en échantillons 16 bits stéréo little-endian
+//
+// sampleRate: la fréquence d'échantillonnage
+// semitones: décalage de hauteur de tout le son, en demi-tons
+// Retourne les échantillons, extinction comprise
+func (p Preset) Render(sampleRate int, semitones float64) []byte {
+	rate := float64(sampleRate)
+	n := int((p.Duration + p.Envelope.Release) * rate)
+	out := make([]byte, n*4)
+
+	phase := 0.0
+	lfsr := uint16(1) // registre du générateur de bruit, comme sur les consoles 8 bits
+	for i := 0; i < n; i++ {
+		t := float64(i) / rate
+		freq := p.Frequency * math.Pow(2, p.pitch(t, semitones)/12)
+
+		phase += freq / rate
+		if phase >= 1 {
+			phase -= math.Floor(phase)
+			bit := (lfsr ^ lfsr>>1) & 1
+			lfsr = lfsr>>1 | bit<<14
+		}
+
+		var sample float64
+		switch p.Wave {
+		case Square:
+			sample = 1
+			if phase >= p.Duty {
+				sample = -1
+			}
+		case Triangle:
+			sample = 4*math.Abs(phase-0.5) - 1
+		case Noise:
+			sample = float64(lfsr&1)*2 - 1
+		}
+
+		value := int16(min(max(sample*p.Volume*p.Envelope.level(t, p.Duration), -1), 1) * math.MaxInt16)
+		binary.LittleEndian.PutUint16(out[i*4:], uint16(value))
+		binary.LittleEndian.PutUint16(out[i*4+2:], uint16(value))
+	}
+	return out
+}
+
+// hauteur du son à l'instant t, en demi-tons au-dessus de Frequency
+func (p Preset) pitch(t, semitones float64) float64 {
+	progress := min(t/p.Duration, 1)
+	pitch := semitones + p.Sweep*progress
+	if len(p.Arpeggio) > 0 {
+		step := min(int(progress*float64(len(p.Arpeggio))), len(p.Arpeggio)-1)
+		pitch += p.Arpeggio[step]
+	}
+	return pitch
+}
+
+// volume de l'enveloppe à l'instant t, pour un son qui dure hold secondes avant son extinction
+func (e Envelope) level(t, hold float64) float64 {
+	if t >= hold {
+		if e.Release <= 0 {
+			return 0
+		}
+		return e.level(hold-1e-9, hold) * max(1-(t-hold)/e.Release, 0)
+	}
+	switch {
+	case t < e.Attack:
+		return t / e.Attack
+	case t < e.Attack+e.Decay:
+		return 1 - (1-e.Sustain)*(t-e.Attack)/e.Decay
+	case e.Decay <= 0 && e.Sustain == 0:
+		return 1 // sans descente, le son reste au volume maximal
+	}
+	return e.Sustain
+}
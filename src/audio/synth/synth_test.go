@@ -0,0 +1,101 @@
+package synth
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const sampleRate = 44100
+
+// nombre de changements de signe du canal gauche, proportionnel à la hauteur du son
+func crossings(pcm []byte) int {
+	count := 0
+	previous := int16(0)
+	for i := 0; i+1 < len(pcm); i += 4 {
+		sample := int16(binary.LittleEndian.Uint16(pcm[i:]))
+		if sample != 0 && previous != 0 && (sample > 0) != (previous > 0) {
+			count++
+		}
+		if sample != 0 {
+			previous = sample
+		}
+	}
+	return count
+}
+
+func TestDefaults(t *testing.T) {
+	presets := Defaults()
+	for _, name := range []string{"move", "eat", "lose", "power_up", "intensity", "stinger", "focus", "food_cue", "proximity"} {
+		p, ok := presets[name]
+		if !ok {
+			t.Errorf("préréglage %q manquant", name)
+			continue
+		}
+		if pcm := p.Render(sampleRate, 0); len(pcm) == 0 || len(pcm)%4 != 0 {
+			t.Errorf("%s: %d octets rendus, attendu un multiple non nul de 4", name, len(pcm))
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	p := Preset{Wave: Square, Frequency: 440, Duty: 0.5, Duration: 0.1, Volume: 1, Envelope: Envelope{Sustain: 1, Release: 0.05}}
+
+	pcm := p.Render(sampleRate, 0)
+	if want := int(0.15*sampleRate) * 4; len(pcm) != want {
+		t.Errorf("longueur %d, attendu %d", len(pcm), want)
+	}
+	if last := int16(binary.LittleEndian.Uint16(pcm[len(pcm)-4:])); last > 100 || last < -100 {
+		t.Errorf("le son devrait être éteint à la fin, dernier échantillon %d", last)
+	}
+	if string(pcm) != string(p.Render(sampleRate, 0)) {
+		t.Error("le rendu d'un préréglage devrait être reproductible")
+	}
+
+	low, high := crossings(p.Render(sampleRate, 0)), crossings(p.Render(sampleRate, 12))
+	if high < low*2-4 || high > low*2+4 {
+		t.Errorf("une octave plus haut devrait doubler la fréquence: %d puis %d passages par zéro", low, high)
+	}
+}
+
+func TestParse(t *testing.T) {
+	presets, err := Parse([]byte(`{"blip": {"wave": "triangle", "frequency": 880, "duration": 0.05}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blip := presets["blip"]; blip.Volume != 1 || blip.Duty != 0.5 {
+		t.Errorf("valeurs par défaut non appliquées: %+v", blip)
+	}
+
+	for _, data := range []string{
+		`{"a": {"wave": "sine", "frequency": 440, "duration": 1}}`,
+		`{"a": {"wave": "square", "frequency": 0, "duration": 1}}`,
+		`{"a": {"wave": "square", "frequency": 440}}`,
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s devrait être refusé", data)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sounds.json")
+	if _, err := Load(path); err != nil {
+		t.Fatalf("un fichier absent devrait être ignoré: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"eat": {"wave": "noise", "frequency": 1000, "duration": 0.2}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	presets, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if presets["eat"].Wave != Noise {
+		t.Errorf("le préréglage du fichier devrait remplacer celui du jeu")
+	}
+	if _, ok := presets["move"]; !ok {
+		t.Errorf("les préréglages intégrés absents du fichier devraient être gardés")
+	}
+}
@@ -0,0 +1,93 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"sync/atomic"
+)
+
+// taille des blocs lus dans le flux d'origine, en octets
+const tempoChunk = 16 * 1024
+
+// vitesse de lecture des musiques, lue par le contexte audio depuis une autre goroutine
+var tempo atomic.Uint64
+
+func init() {
+	tempo.Store(math.Float64bits(1))
+}
+
+// SetTempo change la vitesse de lecture des musiques, 1 pour la vitesse normale
+// La hauteur change avec la vitesse, comme sur un tourne-disque
+func SetTempo(rate float64) {
+	tempo.Store(math.Float64bits(min(max(rate, 0.5), 2)))
+}
+
+// Tempo retourne la vitesse de lecture des musiques
+func Tempo() float64 {
+	return math.Float64frombits(tempo.Load())
+}
+
+// tempoStream lit un flux 16 bits stéréo à la vitesse réglée par SetTempo, par interpolation linéaire
+type tempoStream struct {
+	src   io.ReadSeeker
+	buf   []byte  // images stéréo lues dans le flux d'origine et pas encore dépassées
+	pos   float64 // position de lecture dans buf, en images
+	eof   bool
+	chunk []byte
+}
+
+func newTempoStream(src io.ReadSeeker) *tempoStream {
+	return &tempoStream{src: src, chunk: make([]byte, tempoChunk)}
+}
+
+func (s *tempoStream) Read(p []byte) (int, error) {
+	rate := Tempo()
+	n := 0
+	for n+4 <= len(p) {
+		i := int(s.pos)
+		if (i+2)*4 > len(s.buf) { // il faut l'image i et la suivante pour interpoler
+			if !s.fill() {
+				break
+			}
+			continue
+		}
+		frac := s.pos - float64(i)
+		for c := 0; c < 4; c += 2 {
+			a := float64(int16(binary.LittleEndian.Uint16(s.buf[i*4+c:])))
+			b := float64(int16(binary.LittleEndian.Uint16(s.buf[(i+1)*4+c:])))
+			binary.LittleEndian.PutUint16(p[n+c:], uint16(int16(a+(b-a)*frac)))
+		}
+		n += 4
+		s.pos += rate
+	}
+	if n == 0 && s.eof {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// oublie les images déjà dépassées et lit la suite du flux d'origine
+// Retourne faux à la fin du flux
+func (s *tempoStream) fill() bool {
+	if s.eof {
+		return false
+	}
+	if skip := min(int(s.pos), len(s.buf)/4); skip > 0 {
+		s.buf = s.buf[skip*4:]
+		s.pos -= float64(skip)
+	}
+
+	read, err := io.ReadFull(s.src, s.chunk)
+	s.buf = append(s.buf, s.chunk[:read-read%4]...)
+	if err != nil {
+		s.eof = true
+	}
+	return read > 0
+}
+
+func (s *tempoStream) Seek(offset int64, whence int) (int64, error) {
+	pos, err := s.src.Seek(offset, whence)
+	s.buf, s.pos, s.eof = nil, 0, false
+	return pos, err
+}
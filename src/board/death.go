@@ -0,0 +1,67 @@
+package board
+
+import "snake-go/src/i18n"
+
+// DeathCause représente la raison pour laquelle le serpent est mort
+type DeathCause int
+
+const (
+	NoDeath DeathCause = iota
+	WallCollision
+	SelfCollision
+	ObstacleCollision
+	OpponentCollision
+	PoisonDeath
+	TimeoutDeath
+)
+
+// String retourne le libellé traduit de la cause de la mort, affiché sur l'écran de fin de partie
+func (c DeathCause) String() string {
+	switch c {
+	case WallCollision:
+		return i18n.T("death.wall")
+	case SelfCollision:
+		return i18n.T("death.self")
+	case ObstacleCollision:
+		return i18n.T("death.obstacle")
+	case OpponentCollision:
+		return i18n.T("death.opponent")
+	case PoisonDeath:
+		return i18n.T("death.poison")
+	case TimeoutDeath:
+		return i18n.T("death.timeout")
+	}
+	return i18n.T("death.unknown")
+}
+
+// ID retourne l'identifiant de la cause, utilisé comme clé dans les statistiques des profils et dans les événements
+func (c DeathCause) ID() string {
+	switch c {
+	case WallCollision:
+		return "wall"
+	case SelfCollision:
+		return "self"
+	case ObstacleCollision:
+		return "obstacle"
+	case OpponentCollision:
+		return "opponent"
+	case PoisonDeath:
+		return "poison"
+	case TimeoutDeath:
+		return "timeout"
+	}
+	return "unknown"
+}
+
+// DeathError est l'erreur renvoyée par la grille lorsque le serpent meurt
+//
+// Cause: la raison de la mort
+// Position: la cellule où a eu lieu la collision
+type DeathError struct {
+	Cause    DeathCause
+	Position Position
+}
+
+func (e *DeathError) Error() string {
+	return i18n.T("death.error", e.Cause.String())
+}
@@ -0,0 +1,545 @@
+// Package board contient le plateau d'une partie : le serpent, la nourriture, les obstacles, les collisions
+// et la disposition des sprites, sans dépendre d'ebiten
+//
+// Le jeu dessine la grille avec ebiten, les rendus hors jeu la dessinent en mémoire avec Render :
+// une partie enregistrée peut être rejouée et filmée sans fenêtre ni carte graphique.
+package board
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+
+	"snake-go/src/config"
+	"snake-go/src/event"
+	"snake-go/src/theme"
+)
+
+// Directions du snake
+type Direction int
+
+const (
+	Up Direction = iota
+	Down
+	Left
+	Right
+)
+
+// opposée d'une direction, le serpent ne peut pas faire demi-tour
+func (d Direction) opposite() Direction {
+	switch d {
+	case Up:
+		return Down
+	case Down:
+		return Up
+	case Left:
+		return Right
+	}
+	return Left
+}
+
+type Position struct {
+	X, Y int
+}
+
+// conversion pour les événements
+func (p Position) point() image.Point {
+	return image.Pt(p.X, p.Y)
+}
+
+// Grid représente la grille du jeu, contenant le snake, la nourriture, les obstacles...
+type Grid struct {
+	cells         [][]bool
+	snake         []Position
+	food          Position
+	obstacles     []Position
+	direction     Direction
+	nextDirection Direction
+	width, height int
+	collision     *Position // cellule de la collision qui a tué le serpent, nil tant qu'il est en vie
+	foodSteps     int       // déplacements depuis l'apparition de la nourriture
+	foodShortest  int       // plus court chemin jusqu'à la nourriture lors de son apparition, sans compter les obstacles
+	rng           *rand.Rand
+	ticks         int           // horloge des animations, avance à chaque tick même entre deux déplacements
+	lastMeal      int           // tick du dernier repas, qui lance l'animation de la bouche, -1 avant le premier
+	diedAt        int           // tick de la collision, qui lance l'animation de la mort
+	Skin          *theme.Theme  // thème dont les sprites dessinent le serpent, le thème courant si nil
+	Tint          color.Color   // couleur appliquée aux sprites du serpent, aucune si nil
+	Access        config.Access // options d'accessibilité : contours, formes et animations figées
+}
+
+// Sprite est une image d'animation placée sur le plateau
+type Sprite struct {
+	Theme *theme.Theme // thème dont la planche contient l'image
+	Frame theme.Frame
+	X, Y  float64     // coin supérieur gauche de la case à l'écran
+	Tint  color.Color // couleur appliquée à l'image, aucune si nil
+}
+
+// Mode de jeu dont les plateaux ont des obstacles
+const ChallengeMode = "Challenge"
+
+// Ralentissement du serpent avec l'assistance à la vitesse, en pourcentage de l'intervalle entre deux déplacements
+const AssistSlowdown = 150
+
+// MinInterval retourne l'intervalle minimal entre deux déplacements, en ticks, que les accélérations ne dépassent pas
+//
+// assisted: vrai si la partie est jouée avec l'assistance à la vitesse
+func MinInterval(assisted bool) int {
+	if assisted {
+		return 3 * AssistSlowdown / 100
+	}
+	return 3
+}
+
+// ObstacleCount retourne le nombre d'obstacles d'un plateau du mode Challenge
+//
+// difficulty: l'identifiant de la difficulté (Facile, Normal, Difficile)
+func ObstacleCount(difficulty string) int {
+	switch difficulty {
+	case "Facile":
+		return 2
+	case "Normal":
+		return 3
+	case "Difficile":
+		return 5
+	}
+	return 0
+}
+
+// New initialise une nouvelle grille sans obstacles
+//
+// width, height: dimensions de la grille, en cellules
+// rng: générateur qui place la nourriture, la même graine donne la même partie
+// Retourne une nouvelle grille initialisée
+func New(width, height int, rng *rand.Rand) *Grid {
+	initialDirection := Right
+
+	grid := &Grid{
+		cells:         make([][]bool, height),
+		snake:         []Position{{X: width / 2, Y: height / 2}},
+		direction:     initialDirection,
+		nextDirection: initialDirection,
+		width:         width,
+		height:        height,
+		rng:           rng,
+		lastMeal:      -1,
+	}
+	for i := range grid.cells {
+		grid.cells[i] = make([]bool, width)
+	}
+	grid.cells[grid.snake[0].Y][grid.snake[0].X] = true
+	grid.placeFood()
+	return grid
+}
+
+// NewWithObstacles initialise une nouvelle grille avec des obstacles
+//
+// width, height: dimensions de la grille, en cellules
+// obstacles: le nombre d'obstacles, donné par ObstacleCount selon la difficulté
+// rng: générateur qui place la nourriture et les obstacles, la même graine donne la même partie
+// Retourne une nouvelle grille avec obstacles
+func NewWithObstacles(width, height, obstacles int, rng *rand.Rand) *Grid {
+	initialDirection := Right
+
+	grid := &Grid{
+		cells:         make([][]bool, height),
+		snake:         []Position{{X: width / 2, Y: height / 2}},
+		direction:     initialDirection,
+		nextDirection: initialDirection,
+		width:         width,
+		height:        height,
+		rng:           rng,
+		lastMeal:      -1,
+	}
+	for i := range grid.cells {
+		grid.cells[i] = make([]bool, width)
+	}
+	grid.cells[grid.snake[0].Y][grid.snake[0].X] = true
+	grid.placeFood()
+	grid.placeObstacles(obstacles)
+	return grid
+}
+
+// NewGame initialise la grille d'une partie : sans obstacles en mode Classique,
+// avec ceux de la difficulté en mode Challenge
+//
+// mode: le mode de jeu (Classique, Challenge)
+// difficulty: l'identifiant de la difficulté
+// width, height, rng: comme pour New
+func NewGame(mode, difficulty string, width, height int, rng *rand.Rand) *Grid {
+	if mode == ChallengeMode {
+		return NewWithObstacles(width, height, ObstacleCount(difficulty), rng)
+	}
+	return New(width, height, rng)
+}
+
+// placeFood place aléatoirement la nourriture sur la grille
+func (g *Grid) placeFood() {
+	margin := 1
+
+	foodX := g.rng.Intn(g.width-2*margin) + margin
+	foodY := g.rng.Intn(g.height-2*margin) + margin
+	g.food = Position{X: foodX, Y: foodY}
+
+	// vérifier que la nourriture n'est pas placée sur le serpent
+	for _, pos := range g.snake {
+		if pos == g.food {
+			g.placeFood()
+			return
+		}
+	}
+	// vérifier que la nourriture n'est pas placée sur un obstacle
+	for _, pos := range g.obstacles {
+		if pos == g.food {
+			g.placeFood()
+			return
+		}
+	}
+
+	head := g.snake[0]
+	g.foodSteps = 0
+	g.foodShortest = abs(g.food.X-head.X) + abs(g.food.Y-head.Y)
+}
+
+// valeur absolue d'un entier
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// placeObstacles place des obstacles sur la grille
+//
+// obstacleCount: le nombre d'obstacles
+func (g *Grid) placeObstacles(obstacleCount int) {
+	margin := 1
+
+	for i := 0; i < obstacleCount; i++ {
+		obstacleX := g.rng.Intn(g.width-2*margin) + margin
+		obstacleY := g.rng.Intn(g.height-2*margin) + margin
+		obstacle := Position{X: obstacleX, Y: obstacleY}
+
+		for g.cells[obstacle.Y][obstacle.X] {
+			obstacleX = g.rng.Intn(g.width-2*margin) + margin
+			obstacleY = g.rng.Intn(g.height-2*margin) + margin
+			obstacle = Position{X: obstacleX, Y: obstacleY}
+		}
+
+		g.obstacles = append(g.obstacles, obstacle)
+		g.cells[obstacle.Y][obstacle.X] = true
+	}
+}
+
+// Turn choisit la direction du prochain déplacement, un demi-tour est ignoré
+func (g *Grid) Turn(direction Direction) {
+	if g.direction != direction.opposite() {
+		g.nextDirection = direction
+	}
+}
+
+// Step fait avancer le serpent d'une case, vérifie les collisions et mange la nourriture
+// Le déplacement ne dépend que de la grille et des appels à Turn, ce qui permet de rejouer une partie
+//
+// bus: reçoit les événements Turned, Moved, FoodEaten et Died, peut être nil
+// Retourne une DeathError en cas de collision avec les murs, le serpent lui-même ou un obstacle
+func (g *Grid) Step(bus *event.Bus) error {
+	if len(g.snake) == 1 {
+		g.snake = append(g.snake, g.snake[0])
+	}
+
+	if g.nextDirection != g.direction {
+		g.direction = g.nextDirection
+		event.Publish(bus, event.Turned{Direction: int(g.direction)})
+	}
+	head := g.snake[0]
+	newHead := head
+	switch g.direction {
+	case Up:
+		newHead.Y--
+	case Down:
+		newHead.Y++
+	case Left:
+		newHead.X--
+	case Right:
+		newHead.X++
+	}
+
+	// vérifier les collisions avec les murs
+	if newHead.X < 0 || newHead.X >= g.width || newHead.Y < 0 || newHead.Y >= g.height {
+		return g.die(bus, WallCollision, newHead)
+	}
+
+	// vérifier collision avec le serpent
+	for _, segment := range g.snake[1:] {
+		if newHead == segment {
+			return g.die(bus, SelfCollision, newHead)
+		}
+	}
+
+	// vérifier collision avec les obstacles
+	for _, obstacle := range g.obstacles {
+		if newHead == obstacle {
+			return g.die(bus, ObstacleCollision, newHead)
+		}
+	}
+
+	g.foodSteps++
+	event.Publish(bus, event.Moved{Head: newHead.point(), Food: g.food.point(), Clearance: g.clearance(newHead)})
+
+	// manger la nourriture
+	if newHead == g.food {
+		g.snake = append([]Position{newHead}, g.snake...)
+		g.lastMeal = g.ticks
+		event.Publish(bus, event.FoodEaten{Position: newHead.point(), Length: len(g.snake), Detour: g.foodSteps > g.foodShortest})
+		g.placeFood()
+	} else {
+		g.snake = append([]Position{newHead}, g.snake[:len(g.snake)-1]...)
+	}
+
+	return nil
+}
+
+// clearance compte les cases libres entre une cellule et le mur ou l'obstacle le plus proche
+// La distance à un obstacle se compte comme le roi aux échecs, diagonales comprises
+func (g *Grid) clearance(pos Position) int {
+	free := min(pos.X, pos.Y, g.width-1-pos.X, g.height-1-pos.Y)
+	for _, obstacle := range g.obstacles {
+		free = min(free, max(abs(obstacle.X-pos.X), abs(obstacle.Y-pos.Y))-1)
+	}
+	return free
+}
+
+// die enregistre la cellule de la collision, publie l'événement Died et construit l'erreur correspondante
+//
+// bus: reçoit l'événement Died, peut être nil
+// cause: la raison de la mort
+// pos: la cellule où a eu lieu la collision
+// Retourne une DeathError décrivant la mort
+func (g *Grid) die(bus *event.Bus, cause DeathCause, pos Position) error {
+	g.collision = &pos
+	g.diedAt = g.ticks
+	event.Publish(bus, event.Died{Cause: cause.ID(), Position: pos.point(), Length: len(g.snake)})
+	return &DeathError{Cause: cause, Position: pos}
+}
+
+// horloge des animations, arrêtée au début des animations quand les mouvements sont réduits
+func (g *Grid) clock() int {
+	if g.Access.ReducedMotion {
+		return 0
+	}
+	return g.ticks
+}
+
+// Food retourne la cellule de la nourriture
+func (g *Grid) Food() Position {
+	return g.food
+}
+
+// Obstacles retourne les cellules des obstacles
+func (g *Grid) Obstacles() []Position {
+	return g.obstacles
+}
+
+// Collision retourne la cellule de la collision qui a tué le serpent, ramenée juste au bord du plateau
+// pour une collision avec un mur, et faux tant que le serpent est en vie
+func (g *Grid) Collision() (Position, bool) {
+	if g.collision == nil {
+		return Position{}, false
+	}
+	pos := *g.collision
+	pos.X = min(max(pos.X, -1), g.width)
+	pos.Y = min(max(pos.Y, -1), g.height)
+	return pos, true
+}
+
+// Animate fait avancer les animations des sprites d'un tick
+func (g *Grid) Animate() {
+	g.ticks++
+}
+
+// SnakeLength retourne la longueur actuelle du serpent
+func (g *Grid) SnakeLength() int {
+	return len(g.snake)
+}
+
+// Sprites retourne les images à dessiner sur le plateau, dans l'ordre : le serpent, la pomme puis les obstacles
+//
+// layout: la disposition du plateau à l'écran
+func (g *Grid) Sprites(layout Layout) []Sprite {
+	currentTheme := theme.Current()
+
+	// le serpent, avec l'apparence du profil du joueur
+	skin := currentTheme
+	if g.Skin != nil {
+		skin = g.Skin
+	}
+	var sprites []Sprite
+	for i, pos := range g.snake {
+		var segmentType string
+		var direction Direction
+		var nextDirection Direction
+
+		// déterminer le type de segment (tête, corps, queue) et les directions
+		if i == 0 {
+			segmentType = "head"
+			direction = g.direction
+			if len(g.snake) > 1 {
+				nextPos := g.snake[i+1]
+				if pos.X < nextPos.X {
+					nextDirection = Left
+				} else if pos.X > nextPos.X {
+					nextDirection = Right
+				} else if pos.Y < nextPos.Y {
+					nextDirection = Up
+				} else {
+					nextDirection = Down
+				}
+			}
+		} else if i == len(g.snake)-1 {
+			segmentType = "tail"
+			prevPos := g.snake[i-1]
+			if pos.X < prevPos.X {
+				direction = Left
+			} else if pos.X > prevPos.X {
+				direction = Right
+			} else if pos.Y < prevPos.Y {
+				direction = Up
+			} else {
+				direction = Down
+			}
+		} else {
+			segmentType = "body"
+			prevPos := g.snake[i-1]
+			if pos.X < prevPos.X {
+				direction = Left
+			} else if pos.X > prevPos.X {
+				direction = Right
+			} else if pos.Y < prevPos.Y {
+				direction = Up
+			} else {
+				direction = Down
+			}
+
+			nextPos := g.snake[i+1]
+			if pos.X < nextPos.X {
+				nextDirection = Left
+			} else if pos.X > nextPos.X {
+				nextDirection = Right
+			} else if pos.Y < nextPos.Y {
+				nextDirection = Up
+			} else {
+				nextDirection = Down
+			}
+		}
+
+		key := spriteKey(segmentType, direction, nextDirection)
+		frame := skin.Frame(key, g.clock())
+		if i == 0 {
+			frame = g.headFrame(skin, key)
+		}
+		x, y := layout.CellPosition(pos.X, pos.Y)
+		sprites = append(sprites, Sprite{skin, frame, x, y, g.Tint})
+	}
+
+	// la pomme
+	x, y := layout.CellPosition(g.food.X, g.food.Y)
+	sprites = append(sprites, Sprite{currentTheme, currentTheme.Frame("apple", g.clock()), x, y, nil})
+
+	// les obstacles, décalés dans leur animation pour ne pas bouger tous ensemble
+	for _, pos := range g.obstacles {
+		x, y := layout.CellPosition(pos.X, pos.Y)
+		frame := currentTheme.Frame("obstacle", g.clock()+37*pos.X+53*pos.Y)
+		sprites = append(sprites, Sprite{currentTheme, frame, x, y, nil})
+	}
+	return sprites
+}
+
+// Layout calcule la disposition de la grille pour un écran de la taille donnée
+//
+// screenWidth, screenHeight: la taille de l'écran logique
+// Retourne la position et la taille des cellules de la grille
+func (g *Grid) Layout(screenWidth, screenHeight int) Layout {
+	return ComputeLayout(screenWidth, screenHeight, g.width, g.height)
+}
+
+// nom de l'élément de la planche de sprites correspondant au type et à la direction du segment du serpent
+//
+// segmentType: le type de segment (tête, corps, queue)
+// direction: la direction actuelle du segment
+// nextDirection: la direction du prochain segment pour déterminer les coins
+// Retourne le nom de l'élément (head_up, body_h, turn_ur...)
+func spriteKey(segmentType string, direction Direction, nextDirection Direction) string {
+	var segmentKey string
+
+	switch segmentType {
+	case "head":
+		switch direction {
+		case Up:
+			segmentKey = "head_up"
+		case Down:
+			segmentKey = "head_down"
+		case Left:
+			segmentKey = "head_left"
+		case Right:
+			segmentKey = "head_right"
+		}
+	case "tail":
+		switch direction {
+		case Up:
+			segmentKey = "tail_up"
+		case Down:
+			segmentKey = "tail_down"
+		case Left:
+			segmentKey = "tail_left"
+		case Right:
+			segmentKey = "tail_right"
+		}
+	case "body":
+		if direction == Up || direction == Down {
+			segmentKey = "body_v"
+		} else {
+			segmentKey = "body_h"
+		}
+
+		switch {
+		case direction == Up && nextDirection == Right:
+			segmentKey = "turn_dl"
+		case direction == Up && nextDirection == Left:
+			segmentKey = "turn_dr"
+		case direction == Down && nextDirection == Right:
+			segmentKey = "turn_ul"
+		case direction == Down && nextDirection == Left:
+			segmentKey = "turn_ur"
+		case direction == Left && nextDirection == Up:
+			segmentKey = "turn_dr"
+		case direction == Left && nextDirection == Down:
+			segmentKey = "turn_ur"
+		case direction == Right && nextDirection == Up:
+			segmentKey = "turn_dl"
+		case direction == Right && nextDirection == Down:
+			segmentKey = "turn_ul"
+		}
+	}
+
+	return segmentKey
+}
+
+// image de la tête du serpent : l'animation de la mort après une collision,
+// celle de la bouche juste après un repas, sinon l'animation de la tête au repos
+//
+// t: le thème dont les sprites dessinent le serpent
+// key: le nom de l'élément de la tête dans sa direction (head_up...)
+func (g *Grid) headFrame(t *theme.Theme, key string) theme.Frame {
+	if g.Access.ReducedMotion {
+		return t.Frame(key, 0)
+	}
+	if g.collision != nil && t.Animation(key+"_death") != nil {
+		return t.Frame(key+"_death", g.ticks-g.diedAt)
+	}
+	if eat := t.Animation(key + "_eat"); eat != nil && g.lastMeal >= 0 && g.ticks-g.lastMeal < eat.Duration() {
+		return t.Frame(key+"_eat", g.ticks-g.lastMeal)
+	}
+	return t.Frame(key, g.ticks)
+}
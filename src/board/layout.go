@@ -0,0 +1,55 @@
+package board
+
+import (
+	"image"
+
+	"snake-go/src/constants"
+)
+
+// Layout décrit la position et la taille du plateau dans l'écran
+type Layout struct {
+	X, Y     int // coin supérieur gauche de la zone de jeu, hors bordure
+	CellSize int
+	Cols     int
+	Rows     int
+}
+
+// ComputeLayout calcule la taille des cellules pour que le plateau tienne dans l'écran, puis le centre
+//
+// screenWidth, screenHeight: la taille de l'écran logique
+// cols, rows: les dimensions du plateau, en cellules
+// Retourne la disposition du plateau
+func ComputeLayout(screenWidth, screenHeight, cols, rows int) Layout {
+	available := func(size int) int {
+		return size - 2*(constants.BoardMargin+constants.BorderThickness)
+	}
+	cellSize := max(1, min(available(screenWidth)/cols, available(screenHeight)/rows))
+
+	return Layout{
+		X:        (screenWidth - cols*cellSize) / 2,
+		Y:        (screenHeight - rows*cellSize) / 2,
+		CellSize: cellSize,
+		Cols:     cols,
+		Rows:     rows,
+	}
+}
+
+// Width retourne la largeur de la zone de jeu en pixels
+func (l Layout) Width() int {
+	return l.Cols * l.CellSize
+}
+
+// Height retourne la hauteur de la zone de jeu en pixels
+func (l Layout) Height() int {
+	return l.Rows * l.CellSize
+}
+
+// Rect retourne le rectangle de la zone de jeu, agrandi de margin pixels de chaque côté
+func (l Layout) Rect(margin int) image.Rectangle {
+	return image.Rect(l.X-margin, l.Y-margin, l.X+l.Width()+margin, l.Y+l.Height()+margin)
+}
+
+// CellPosition retourne la position à l'écran du coin supérieur gauche d'une cellule
+func (l Layout) CellPosition(x, y int) (float64, float64) {
+	return float64(l.X + x*l.CellSize), float64(l.Y + y*l.CellSize)
+}
@@ -0,0 +1,39 @@
+package board
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"snake-go/src/constants"
+	"snake-go/src/theme"
+)
+
+// Render dessine la grille comme le jeu, sans carte graphique, dans une image en mémoire :
+// la bordure, le fond, les sprites et la cellule de la collision
+// Les contours et les formes des options d'accessibilité ne sont pas dessinés
+//
+// dst: l'image sur laquelle dessiner
+func (g *Grid) Render(dst draw.Image) {
+	currentTheme := theme.Current()
+	bounds := dst.Bounds()
+	layout := g.Layout(bounds.Dx(), bounds.Dy())
+	cellSize := float64(layout.CellSize)
+
+	fill := func(rect image.Rectangle, c color.Color) {
+		draw.Draw(dst, rect.Add(bounds.Min), image.NewUniform(c), image.Point{}, draw.Over)
+	}
+	fill(layout.Rect(constants.BorderThickness), currentTheme.Palette.Border)
+	fill(layout.Rect(0), currentTheme.Palette.Board)
+
+	for _, s := range g.Sprites(layout) {
+		x, y := s.X+float64(bounds.Min.X), s.Y+float64(bounds.Min.Y)
+		s.Theme.RenderFrame(dst, s.Frame, x, y, cellSize, s.Tint)
+	}
+
+	// la cellule de la collision, comme sur l'image figée de fin de partie
+	if pos, ok := g.Collision(); ok {
+		x, y := layout.CellPosition(pos.X, pos.Y)
+		fill(image.Rect(int(x), int(y), int(x)+layout.CellSize, int(y)+layout.CellSize), currentTheme.Palette.CollisionColor)
+	}
+}
@@ -0,0 +1,174 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+)
+
+// signature de tout fichier PNG
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// EncodeAPNG écrit les images d'un clip dans un PNG animé joué en boucle, sans perte de couleurs
+//
+// frames: les images du clip, toutes de la même taille et opaques
+// delay: la durée d'une image en centièmes de seconde
+func EncodeAPNG(w io.Writer, frames []*image.RGBA, delay int) error {
+	a := NewAPNGWriter(w, len(frames), delay)
+	for _, frame := range frames {
+		if err := a.Add(frame); err != nil {
+			return err
+		}
+	}
+	return a.Close()
+}
+
+// APNGWriter écrit un PNG animé joué en boucle image par image, sans garder les images précédentes
+// Chaque image est encodée par image/png, ses données IDAT sont reprises dans les blocs d'animation
+// fcTL et fdAT : les lecteurs qui ne connaissent pas l'APNG affichent la première image
+type APNGWriter struct {
+	w        io.Writer
+	count    int // nombre d'images annoncé au début du fichier
+	delay    int
+	size     image.Point
+	written  int
+	sequence uint32 // numéro des blocs fcTL et fdAT, dans l'ordre du fichier
+}
+
+// NewAPNGWriter prépare l'écriture d'un PNG animé
+//
+// w: la destination du fichier
+// count: le nombre d'images qui seront ajoutées, écrit avant la première
+// delay: la durée d'une image en centièmes de seconde
+func NewAPNGWriter(w io.Writer, count, delay int) *APNGWriter {
+	return &APNGWriter{w: w, count: count, delay: delay}
+}
+
+// Add écrit l'image suivante, de la même taille que la première et opaque
+// L'image peut être réutilisée dès le retour de Add
+func (a *APNGWriter) Add(frame *image.RGBA) error {
+	if a.written == a.count {
+		return errors.New("plus d'images que le nombre annoncé")
+	}
+	if a.written == 0 {
+		a.size = frame.Rect.Size()
+	} else if frame.Rect.Size() != a.size {
+		return errors.New("les images d'un APNG doivent avoir la même taille")
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, frame); err != nil {
+		return err
+	}
+	chunks, err := readChunks(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	first := a.written == 0
+	a.written++
+	if first {
+		if _, err := a.w.Write(pngSignature); err != nil {
+			return err
+		}
+		for _, c := range chunks {
+			if c.kind == "IHDR" {
+				if err := writeChunk(a.w, "IHDR", c.data); err != nil {
+					return err
+				}
+			}
+		}
+		actl := make([]byte, 8)
+		binary.BigEndian.PutUint32(actl[0:], uint32(a.count))
+		binary.BigEndian.PutUint32(actl[4:], 0) // lecture en boucle
+		if err := writeChunk(a.w, "acTL", actl); err != nil {
+			return err
+		}
+	}
+
+	fctl := make([]byte, 26)
+	binary.BigEndian.PutUint32(fctl[0:], a.sequence)
+	binary.BigEndian.PutUint32(fctl[4:], uint32(a.size.X))
+	binary.BigEndian.PutUint32(fctl[8:], uint32(a.size.Y))
+	binary.BigEndian.PutUint16(fctl[20:], uint16(a.delay))
+	binary.BigEndian.PutUint16(fctl[22:], 100) // la durée est en centièmes de seconde
+	// le décalage, la façon d'effacer l'image (aucune) et de la mélanger (remplacement) restent à 0
+	a.sequence++
+	if err := writeChunk(a.w, "fcTL", fctl); err != nil {
+		return err
+	}
+
+	for _, c := range chunks {
+		if c.kind != "IDAT" {
+			continue
+		}
+		if first {
+			err = writeChunk(a.w, "IDAT", c.data)
+		} else {
+			fdat := make([]byte, 4+len(c.data))
+			binary.BigEndian.PutUint32(fdat, a.sequence)
+			copy(fdat[4:], c.data)
+			a.sequence++
+			err = writeChunk(a.w, "fdAT", fdat)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close termine le fichier, qui doit avoir reçu le nombre d'images annoncé
+func (a *APNGWriter) Close() error {
+	switch {
+	case a.written == 0:
+		return errors.New("aucune image à encoder")
+	case a.written != a.count:
+		return errors.New("moins d'images que le nombre annoncé")
+	}
+	return writeChunk(a.w, "IEND", nil)
+}
+
+// chunk est un bloc d'un fichier PNG
+type chunk struct {
+	kind string
+	data []byte
+}
+
+// découpe un fichier PNG en blocs
+func readChunks(data []byte) ([]chunk, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errors.New("signature PNG invalide")
+	}
+	data = data[len(pngSignature):]
+	var chunks []chunk
+	for len(data) >= 12 {
+		length := int(binary.BigEndian.Uint32(data))
+		if len(data) < 12+length {
+			return nil, errors.New("bloc PNG tronqué")
+		}
+		chunks = append(chunks, chunk{kind: string(data[4:8]), data: data[8 : 8+length]})
+		data = data[12+length:]
+	}
+	return chunks, nil
+}
+
+// écrit un bloc PNG : sa longueur, son type, ses données et la somme de contrôle du type et des données
+func writeChunk(w io.Writer, kind string, data []byte) error {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(len(data)))
+	copy(header[4:], kind)
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+	footer := binary.BigEndian.AppendUint32(nil, crc.Sum32())
+	for _, b := range [][]byte{header, data, footer} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
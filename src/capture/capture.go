@@ -0,0 +1,255 @@
+// Package capture enregistre des images de la partie : captures d'écran en PNG et clips des dernières secondes
+// en GIF ou en APNG
+//
+// Le paquet ne dépend pas d'ebiten : le jeu lit les pixels de l'écran et les confie à un Clip,
+// un tampon circulaire qui garde les images les plus récentes jusqu'à ce qu'un clip soit demandé.
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Clip garde les dernières images de la partie, la plus ancienne est remplacée quand il est plein
+type Clip struct {
+	frames []*image.RGBA
+	next   int // emplacement de la prochaine image
+	count  int
+}
+
+// NewClip crée un clip qui garde au plus capacity images
+func NewClip(capacity int) *Clip {
+	return &Clip{frames: make([]*image.RGBA, max(capacity, 1))}
+}
+
+// Cap retourne le nombre d'images que le clip peut garder
+func (c *Clip) Cap() int {
+	return len(c.frames)
+}
+
+// Len retourne le nombre d'images gardées
+func (c *Clip) Len() int {
+	return c.count
+}
+
+// Add ajoute une copie de l'image au clip, rendue opaque
+// L'emplacement de l'image remplacée est réutilisé quand il a la même taille
+func (c *Clip) Add(img *image.RGBA) {
+	slot := c.frames[c.next]
+	if slot == nil || slot.Rect != img.Rect {
+		slot = image.NewRGBA(img.Rect)
+		c.frames[c.next] = slot
+	}
+	copy(slot.Pix, img.Pix)
+	for i := 3; i < len(slot.Pix); i += 4 {
+		slot.Pix[i] = 0xff // les pixels transparents de l'écran sont vus sur fond noir
+	}
+	c.next = (c.next + 1) % len(c.frames)
+	c.count = min(c.count+1, len(c.frames))
+}
+
+// Frames retourne une copie des images gardées, de la plus ancienne à la plus récente
+// Les copies peuvent être encodées pendant que le clip continue d'enregistrer
+func (c *Clip) Frames() []*image.RGBA {
+	frames := make([]*image.RGBA, 0, c.count)
+	for i := 0; i < c.count; i++ {
+		frame := c.frames[(c.next-c.count+i+len(c.frames))%len(c.frames)]
+		copied := image.NewRGBA(frame.Rect)
+		copy(copied.Pix, frame.Pix)
+		frames = append(frames, copied)
+	}
+	return frames
+}
+
+// Reset vide le clip
+func (c *Clip) Reset() {
+	c.next, c.count = 0, 0
+}
+
+// SavePNG enregistre une capture d'écran dans un dossier, sous un nom tiré de la date
+//
+// dir: le dossier des captures, créé si besoin
+// img: l'image à enregistrer
+// date: la date de la capture
+// Retourne le chemin du fichier enregistré
+func SavePNG(dir string, img image.Image, date time.Time) (string, error) {
+	return save(dir, "screenshot", ".png", date, func(w io.Writer) error {
+		return png.Encode(w, img)
+	})
+}
+
+// SaveClip enregistre un clip animé dans un dossier, sous un nom tiré de la date
+//
+// dir: le dossier des captures, créé si besoin
+// frames: les images du clip, toutes de la même taille
+// delay: la durée d'une image en centièmes de seconde
+// apng: enregistre un APNG, aux couleurs exactes, au lieu d'un GIF limité à 256 couleurs
+// date: la date de la capture
+// Retourne le chemin du fichier enregistré
+func SaveClip(dir string, frames []*image.RGBA, delay int, apng bool, date time.Time) (string, error) {
+	if apng {
+		return save(dir, "clip", ".png", date, func(w io.Writer) error {
+			return EncodeAPNG(w, frames, delay)
+		})
+	}
+	return save(dir, "clip", ".gif", date, func(w io.Writer) error {
+		return EncodeGIF(w, frames, delay)
+	})
+}
+
+// crée le fichier d'une capture puis y écrit son contenu
+func save(dir, prefix, ext string, date time.Time, encode func(w io.Writer) error) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, prefix+"-"+date.Format("20060102-150405.000")+ext)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	w := bufio.NewWriter(f)
+	err = encode(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// EncodeGIF écrit les images d'un clip dans un GIF animé joué en boucle
+// Les couleurs du clip sont gardées telles quelles s'il en a 256 au plus, sinon elles sont ramenées
+// à la palette Plan 9
+//
+// delay: la durée d'une image en centièmes de seconde
+func EncodeGIF(w io.Writer, frames []*image.RGBA, delay int) error {
+	pal := clipPalette(frames)
+	nearest := map[color.RGBA]uint8{}
+	anim := &gif.GIF{}
+	for _, frame := range frames {
+		anim.Image = append(anim.Image, toPaletted(frame, pal, nearest))
+		anim.Delay = append(anim.Delay, delay)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// GIFWriter écrit un GIF animé joué en boucle image par image, sans garder les images précédentes
+// Chaque image a sa propre palette : ses couleurs si elle en a 256 au plus, la palette Plan 9 sinon
+type GIFWriter struct {
+	w       io.Writer
+	delay   int
+	started bool
+}
+
+// NewGIFWriter prépare l'écriture d'un GIF animé
+//
+// w: la destination du fichier
+// delay: la durée d'une image en centièmes de seconde
+func NewGIFWriter(w io.Writer, delay int) *GIFWriter {
+	return &GIFWriter{w: w, delay: delay}
+}
+
+// extension qui fait jouer un GIF animé en boucle
+var gifLoop = []byte("\x21\xff\x0bNETSCAPE2.0\x03\x01\x00\x00\x00")
+
+// Add écrit l'image suivante
+// L'image est encodée seule par image/gif, avec une palette globale d'une couleur qui oblige chaque image
+// à garder sa propre palette ; l'en-tête est gardé pour la première image et son bloc d'image est recopié
+// L'image peut être réutilisée dès le retour de Add
+func (g *GIFWriter) Add(frame *image.RGBA) error {
+	var buf bytes.Buffer
+	single := &gif.GIF{
+		Image:  []*image.Paletted{toPaletted(frame, clipPalette([]*image.RGBA{frame}), map[color.RGBA]uint8{})},
+		Delay:  []int{g.delay},
+		Config: image.Config{ColorModel: color.Palette{color.Transparent}, Width: frame.Rect.Dx(), Height: frame.Rect.Dy()},
+	}
+	if err := gif.EncodeAll(&buf, single); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+	// en-tête de 6 octets, description de l'écran de 7 octets puis la palette globale
+	header := 13
+	if data[10]&0x80 != 0 {
+		header += 3 << (data[10]&7 + 1)
+	}
+	if len(data) <= header || data[len(data)-1] != 0x3b {
+		return errors.New("GIF encodé inattendu")
+	}
+	if !g.started {
+		g.started = true
+		if _, err := g.w.Write(data[:header]); err != nil {
+			return err
+		}
+		if _, err := g.w.Write(gifLoop); err != nil {
+			return err
+		}
+	}
+	_, err := g.w.Write(data[header : len(data)-1])
+	return err
+}
+
+// Close termine le fichier
+func (g *GIFWriter) Close() error {
+	if !g.started {
+		return errors.New("aucune image à encoder")
+	}
+	_, err := g.w.Write([]byte{0x3b})
+	return err
+}
+
+// image d'une palette, chaque couleur est cherchée une seule fois dans la palette
+//
+// nearest: les couleurs déjà cherchées, partagées par les images de la même palette
+func toPaletted(frame *image.RGBA, pal color.Palette, nearest map[color.RGBA]uint8) *image.Paletted {
+	paletted := image.NewPaletted(frame.Rect, pal)
+	for y := frame.Rect.Min.Y; y < frame.Rect.Max.Y; y++ {
+		for x := frame.Rect.Min.X; x < frame.Rect.Max.X; x++ {
+			c := frame.RGBAAt(x, y)
+			index, ok := nearest[c]
+			if !ok {
+				index = uint8(pal.Index(c))
+				nearest[c] = index
+			}
+			paletted.SetColorIndex(x, y, index)
+		}
+	}
+	return paletted
+}
+
+// palette d'un clip : ses propres couleurs si elles tiennent dans un GIF, la palette Plan 9 sinon
+func clipPalette(frames []*image.RGBA) color.Palette {
+	seen := map[color.RGBA]bool{}
+	var pal color.Palette
+	for _, frame := range frames {
+		for i := 0; i+3 < len(frame.Pix); i += 4 {
+			c := color.RGBA{R: frame.Pix[i], G: frame.Pix[i+1], B: frame.Pix[i+2], A: frame.Pix[i+3]}
+			if seen[c] {
+				continue
+			}
+			if len(pal) == 256 {
+				return palette.Plan9
+			}
+			seen[c] = true
+			pal = append(pal, c)
+		}
+	}
+	if len(pal) == 0 {
+		pal = append(pal, color.Black)
+	}
+	return pal
+}
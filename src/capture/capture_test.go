@@ -0,0 +1,172 @@
+package capture
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// image unie de 4×3 pixels, transparente pour vérifier que le clip la rend opaque
+func solid(r, g, b uint8) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2] = r, g, b
+	}
+	return img
+}
+
+func TestClipKeepsLatestFrames(t *testing.T) {
+	clip := NewClip(3)
+	for i := 1; i <= 5; i++ {
+		clip.Add(solid(uint8(i), 0, 0))
+	}
+	frames := clip.Frames()
+	if len(frames) != 3 {
+		t.Fatalf("%d images gardées, attendu 3", len(frames))
+	}
+	for i, frame := range frames {
+		if got, want := frame.RGBAAt(0, 0), (color.RGBA{R: uint8(i + 3), A: 0xff}); got != want {
+			t.Errorf("image %d: %v, attendu %v (la plus ancienne d'abord, opaque)", i, got, want)
+		}
+	}
+
+	// les copies ne changent pas quand le clip continue d'enregistrer
+	clip.Add(solid(9, 0, 0))
+	if frames[0].RGBAAt(0, 0).R != 3 {
+		t.Errorf("une image retournée par Frames a été modifiée par Add")
+	}
+
+	clip.Reset()
+	if clip.Len() != 0 || len(clip.Frames()) != 0 {
+		t.Errorf("le clip devrait être vide après Reset")
+	}
+}
+
+func TestEncodeGIF(t *testing.T) {
+	clip := NewClip(2)
+	clip.Add(solid(200, 0, 0))
+	clip.Add(solid(0, 200, 0))
+
+	var buf bytes.Buffer
+	if err := EncodeGIF(&buf, clip.Frames(), 10); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 2 || anim.Delay[1] != 10 {
+		t.Fatalf("%d images de %v centièmes, attendu 2 de 10", len(anim.Image), anim.Delay)
+	}
+	if r, g, _, _ := anim.Image[1].At(0, 0).RGBA(); r != 0 || g>>8 != 200 {
+		t.Errorf("les couleurs d'un clip de moins de 256 couleurs devraient être exactes")
+	}
+}
+
+func TestGIFWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewGIFWriter(&buf, 10)
+	frame := solid(200, 0, 0)
+	for i := 3; i < len(frame.Pix); i += 4 {
+		frame.Pix[i] = 255
+	}
+	for _, g := range []uint8{0, 100, 200} {
+		frame.Pix[1] = g // la même image est réutilisée, comme pendant le rendu d'une partie
+		if err := w.Add(frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 3 || anim.Delay[2] != 10 || anim.LoopCount != 0 {
+		t.Fatalf("%d images de %v centièmes, boucle %d", len(anim.Image), anim.Delay, anim.LoopCount)
+	}
+	for i, g := range []uint32{0, 100, 200} {
+		if r, gg, _, _ := anim.Image[i].At(0, 0).RGBA(); r>>8 != 200 || gg>>8 != g {
+			t.Errorf("image %d: couleur %d %d, attendu 200 %d", i, r>>8, gg>>8, g)
+		}
+	}
+}
+
+func TestEncodeAPNG(t *testing.T) {
+	clip := NewClip(3)
+	for _, r := range []uint8{10, 20, 30} {
+		clip.Add(solid(r, 0, 0))
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeAPNG(&buf, clip.Frames(), 10); err != nil {
+		t.Fatal(err)
+	}
+	chunks, err := readChunks(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := map[string]int{}
+	for _, c := range chunks {
+		count[c.kind]++
+	}
+	if count["acTL"] != 1 || count["fcTL"] != 3 || count["fdAT"] < 2 || count["IEND"] != 1 {
+		t.Errorf("blocs inattendus: %v", count)
+	}
+
+	// les lecteurs PNG ordinaires affichent la première image
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _, _, _ := img.At(0, 0).RGBA(); r>>8 != 10 {
+		t.Errorf("première image attendue, rouge %d", r>>8)
+	}
+
+	if err := EncodeAPNG(&buf, []*image.RGBA{solid(0, 0, 0), image.NewRGBA(image.Rect(0, 0, 1, 1))}, 10); err == nil {
+		t.Errorf("des images de tailles différentes devraient être refusées")
+	}
+
+	// le nombre d'images est écrit avant la première, il doit être respecté
+	w := NewAPNGWriter(&buf, 2, 10)
+	if err := w.Add(solid(0, 0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err == nil {
+		t.Errorf("un APNG avec moins d'images qu'annoncé devrait être refusé")
+	}
+}
+
+func TestSaveNamesFilesByDate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "captures")
+	date := time.Date(2024, 5, 17, 14, 3, 9, 250e6, time.Local)
+
+	path, err := SavePNG(dir, solid(1, 2, 3), date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "screenshot-20240517-140309.250.png"); path != want {
+		t.Errorf("capture enregistrée dans %s, attendu %s", path, want)
+	}
+	path, err = SaveClip(dir, []*image.RGBA{solid(1, 2, 3)}, 10, false, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil || filepath.Ext(path) != ".gif" {
+		t.Errorf("clip GIF attendu, obtenu %s (%v)", path, err)
+	}
+
+	if _, err := SaveClip(dir, nil, 10, true, date); err == nil {
+		t.Errorf("un clip vide ne devrait pas être enregistré")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "clip-20240517-140309.250.png")); err == nil {
+		t.Errorf("le fichier d'un clip qui n'a pas pu être encodé devrait être supprimé")
+	}
+}
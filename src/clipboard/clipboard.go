@@ -0,0 +1,12 @@
+// Package clipboard lit le texte du presse-papiers du système, utilisé pour coller dans les champs de saisie
+package clipboard
+
+import "errors"
+
+// ErrUnavailable est retournée quand le presse-papiers ne peut pas être lu sur ce système
+var ErrUnavailable = errors.New("presse-papiers indisponible")
+
+// ReadText retourne le texte contenu dans le presse-papiers
+func ReadText() (string, error) {
+	return readText()
+}
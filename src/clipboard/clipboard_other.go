@@ -0,0 +1,33 @@
+//go:build !windows
+
+package clipboard
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// commandes qui écrivent le presse-papiers sur leur sortie, essayées dans l'ordre
+var commands = [][]string{
+	{"wl-paste", "--no-newline"},
+	{"xclip", "-selection", "clipboard", "-o"},
+	{"xsel", "--clipboard", "--output"},
+}
+
+func readText() (string, error) {
+	candidates := commands
+	if runtime.GOOS == "darwin" {
+		candidates = [][]string{{"pbpaste"}}
+	}
+	for _, command := range candidates {
+		if _, err := exec.LookPath(command[0]); err != nil {
+			continue
+		}
+		out, err := exec.Command(command[0], command[1:]...).Output()
+		if err != nil {
+			continue
+		}
+		return string(out), nil
+	}
+	return "", ErrUnavailable
+}
@@ -0,0 +1,47 @@
+package clipboard
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// format du texte Unicode dans le presse-papiers de Windows
+const cfUnicodeText = 13
+
+var (
+	user32           = syscall.NewLazyDLL("user32.dll")
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	openClipboard    = user32.NewProc("OpenClipboard")
+	closeClipboard   = user32.NewProc("CloseClipboard")
+	getClipboardData = user32.NewProc("GetClipboardData")
+	globalLock       = kernel32.NewProc("GlobalLock")
+	globalUnlock     = kernel32.NewProc("GlobalUnlock")
+	lstrlenW         = kernel32.NewProc("lstrlenW")
+	moveMemory       = kernel32.NewProc("RtlMoveMemory")
+)
+
+func readText() (string, error) {
+	if ok, _, err := openClipboard.Call(0); ok == 0 {
+		return "", err
+	}
+	defer closeClipboard.Call()
+
+	handle, _, _ := getClipboardData.Call(cfUnicodeText)
+	if handle == 0 {
+		return "", nil
+	}
+	data, _, err := globalLock.Call(handle)
+	if data == 0 {
+		return "", err
+	}
+	defer globalUnlock.Call(handle)
+
+	// le texte est en UTF-16 et se termine par un zéro, il est copié dans un tableau Go avant d'être converti
+	length, _, _ := lstrlenW.Call(data)
+	if length == 0 {
+		return "", nil
+	}
+	chars := make([]uint16, length)
+	moveMemory.Call(uintptr(unsafe.Pointer(&chars[0])), data, length*2)
+	return syscall.UTF16ToString(chars), nil
+}
@@ -0,0 +1,273 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"snake-go/src/constants"
+)
+
+// Config contient les paramètres du jeu sauvegardés entre deux lancements
+type Config struct {
+	BoardWidth  int    `json:"board_width"`
+	BoardHeight int    `json:"board_height"`
+	Fullscreen  bool   `json:"fullscreen"`
+	AssetsDir   string `json:"assets_dir,omitempty"` // dossier dont les fichiers remplacent les ressources embarquées
+	Theme       string `json:"theme"`
+	Language    string `json:"language"`
+
+	// Volumes en pourcentage, le volume général s'applique à la musique et aux effets
+	MasterVolume  int  `json:"master_volume"`
+	MusicVolume   int  `json:"music_volume"`
+	EffectsVolume int  `json:"effects_volume"`
+	Muted         bool `json:"muted"`
+	ShuffleMusic  bool `json:"shuffle_music"` // musiques jouées dans un ordre aléatoire
+	SynthSounds   bool `json:"synth_sounds"`  // bruitages synthétisés au lieu des fichiers du thème
+
+	Effects Effects `json:"effects"`
+	PostFX  PostFX  `json:"post_fx"`
+	Access  Access  `json:"accessibility"`
+	Capture Capture `json:"capture"`
+}
+
+// Effects indique les effets visuels activés pendant une partie
+type Effects struct {
+	Particles  bool `json:"particles"`   // éclats de pomme, débris à la mort et traînée à grande vitesse
+	Shake      bool `json:"shake"`       // tremblement de l'écran lors d'une collision
+	Flash      bool `json:"flash"`       // flash rouge lors d'une collision
+	ScorePulse bool `json:"score_pulse"` // le score grossit un instant quand il augmente
+	SlowMotion bool `json:"slow_motion"` // ralenti à la mort avant l'écran de fin de partie
+}
+
+// PostFX indique les shaders appliqués à l'écran entier
+type PostFX struct {
+	CRT       bool `json:"crt"`       // lignes de balayage et écran bombé d'un vieux moniteur
+	Bloom     bool `json:"bloom"`     // halo lumineux autour de la pomme
+	Vignette  bool `json:"vignette"`  // bords de l'écran assombris
+	Grayscale bool `json:"grayscale"` // l'écran de fin de partie passe en niveaux de gris
+}
+
+// Vision des couleurs, dont les couleurs du jeu sont adaptées aux daltonismes
+const (
+	NormalVision = ""
+	Protanopia   = "protanopia"
+	Deuteranopia = "deuteranopia"
+	Tritanopia   = "tritanopia"
+)
+
+// ColorVisions liste les visions des couleurs proposées, dans l'ordre des paramètres
+var ColorVisions = []string{NormalVision, Protanopia, Deuteranopia, Tritanopia}
+
+// Limites de la taille de l'interface, en pourcentage
+const (
+	MinUIScale = 100
+	MaxUIScale = 200
+)
+
+// Access regroupe les options d'accessibilité
+type Access struct {
+	ColorVision   string `json:"color_vision"`   // couleurs adaptées à un daltonisme, NormalVision sinon
+	HighContrast  bool   `json:"high_contrast"`  // serpent, pomme et obstacles entourés d'un contour
+	ShapeCues     bool   `json:"shape_cues"`     // formes dessinées sur la pomme et les obstacles pour les distinguer sans les couleurs
+	UIScale       int    `json:"ui_scale"`       // taille de l'interface en pourcentage
+	ReducedMotion bool   `json:"reduced_motion"` // sprites immobiles, sans tremblement, flash ni particules
+	AssistSpeed   bool   `json:"assist_speed"`   // serpent plus lent, les scores ne comptent pas dans les classements
+	Narration     bool   `json:"narration"`      // le widget qui reçoit le focus dans les menus est annoncé par un bip et lu à voix haute
+	AudioCues     bool   `json:"audio_cues"`     // la direction de la pomme et la proximité des murs et des obstacles s'entendent
+}
+
+// Limites de la durée des clips, en secondes
+const (
+	MinClipSeconds = 5
+	MaxClipSeconds = 30
+)
+
+// Capture règle les clips enregistrés pendant la partie
+type Capture struct {
+	ClipSeconds int  `json:"clip_seconds"` // durée des clips, les dernières secondes de la partie
+	APNG        bool `json:"apng"`         // clips en APNG aux couleurs exactes au lieu de GIF
+}
+
+// Default retourne la configuration par défaut
+func Default() Config {
+	return Config{
+		BoardWidth:  constants.DefaultBoardWidth,
+		BoardHeight: constants.DefaultBoardHeight,
+		Theme:       "classic",
+		Language:    "fr",
+
+		MasterVolume:  100,
+		MusicVolume:   100,
+		EffectsVolume: 100,
+		ShuffleMusic:  true,
+
+		Effects: Effects{Particles: true, Shake: true, Flash: true, ScorePulse: true, SlowMotion: true},
+		PostFX:  PostFX{Grayscale: true},
+		Access:  Access{UIScale: MinUIScale},
+		Capture: Capture{ClipSeconds: 10},
+	}
+}
+
+// Dir retourne le dossier où sont enregistrés les fichiers du jeu (configuration, profils...)
+func Dir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "snake-go"), nil
+}
+
+// Load charge la configuration depuis le disque
+// Retourne la configuration par défaut si le fichier n'existe pas ou ne peut pas être lu
+func Load() Config {
+	cfg := Default()
+
+	path, err := path()
+	if err != nil {
+		log.Printf("Impossible de trouver le dossier de configuration: %v", err)
+		return cfg
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Impossible de lire la configuration: %v", err)
+		}
+		return cfg
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		log.Printf("Configuration invalide, utilisation des valeurs par défaut: %v", err)
+		return Default()
+	}
+
+	cfg.Normalize()
+	return cfg
+}
+
+// Save enregistre la configuration sur le disque
+func (c Config) Save() error {
+	path, err := path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Normalize ramène les valeurs hors limites dans les bornes autorisées
+func (c *Config) Normalize() {
+	c.BoardWidth = ClampBoardSize(c.BoardWidth)
+	c.BoardHeight = ClampBoardSize(c.BoardHeight)
+	c.MasterVolume = clampVolume(c.MasterVolume)
+	c.MusicVolume = clampVolume(c.MusicVolume)
+	c.EffectsVolume = clampVolume(c.EffectsVolume)
+	c.Access.UIScale = min(max(c.Access.UIScale, MinUIScale), MaxUIScale)
+	c.Capture.ClipSeconds = min(max(c.Capture.ClipSeconds, MinClipSeconds), MaxClipSeconds)
+	if !slices.Contains(ColorVisions, c.Access.ColorVision) {
+		c.Access.ColorVision = NormalVision
+	}
+}
+
+// ClampBoardSize limite une dimension du plateau entre MinBoardSize et MaxBoardSize cellules
+func ClampBoardSize(size int) int {
+	return min(max(size, constants.MinBoardSize), constants.MaxBoardSize)
+}
+
+// limite un volume entre 0 et 100 %
+func clampVolume(volume int) int {
+	return min(max(volume, 0), 100)
+}
+
+// ThemesDir retourne le dossier où sont cherchés les thèmes personnalisés
+func ThemesDir() string {
+	dir, err := Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "themes")
+}
+
+// LocalesDir retourne le dossier où sont cherchés les catalogues de traduction supplémentaires
+func LocalesDir() string {
+	dir, err := Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "locales")
+}
+
+// MusicDir retourne le dossier où sont cherchées les musiques ajoutées par le joueur
+func MusicDir() string {
+	dir, err := Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "music")
+}
+
+// SoundPresetsPath retourne le fichier où le joueur peut redéfinir les bruitages synthétisés
+func SoundPresetsPath() string {
+	dir, err := Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "sounds.json")
+}
+
+// ProfilesPath retourne le fichier où sont enregistrés les profils des joueurs
+func ProfilesPath() string {
+	dir, err := Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "profiles.json")
+}
+
+// ExportsDir retourne le dossier où sont exportées les statistiques
+func ExportsDir() string {
+	dir, err := Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "exports")
+}
+
+// ReplaysDir retourne le dossier où sont enregistrées les parties
+func ReplaysDir() string {
+	dir, err := Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "replays")
+}
+
+// CapturesDir retourne le dossier où sont enregistrées les captures d'écran et les clips
+func CapturesDir() string {
+	dir, err := Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "captures")
+}
+
+// chemin du fichier de configuration
+func path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
@@ -1,15 +1,22 @@
 package constants
 
 const (
-	ScreenWidth      = 1280
-	ScreenHeight     = 720
-	GridWidth        = 600
-	GridHeight       = 600
-	CellSize         = 30
-	BorderThickness  = 5
-	SampleRate       = 44100
-	MoveVolume       = 0.8
-	EatVolume        = 0.8
-	LoseVolume       = 0.8
-	BackgroundVolume = 0.3
+	ScreenWidth        = 1280 // taille par défaut de la fenêtre
+	ScreenHeight       = 720
+	MinScreenWidth     = 1024 // taille logique minimale, en dessous l'écran est réduit par ebiten
+	MinScreenHeight    = 680
+	DefaultBoardWidth  = 20 // dimensions par défaut du plateau, en cellules
+	DefaultBoardHeight = 20
+	MinBoardSize       = 10
+	MaxBoardSize       = 100
+	BoardMargin        = 40 // marge minimale autour du plateau, en pixels
+	BorderThickness    = 5
+	SampleRate         = 44100
+	MoveVolume         = 0.8
+	EatVolume          = 0.8
+	LoseVolume         = 0.8
+	PowerUpVolume      = 0.8
+	StingerVolume      = 0.8
+	CueVolume          = 0.6
+	BackgroundVolume   = 0.3
 )
@@ -0,0 +1,31 @@
+// Package event transmet les événements d'une partie (pomme mangée, virage, mort...) aux parties du jeu
+// qui y réagissent : sons, affichage, succès, statistiques et enregistrement des parties
+package event
+
+import "reflect"
+
+// Bus distribue chaque événement publié aux fonctions abonnées à son type, dans l'ordre d'abonnement
+type Bus struct {
+	handlers map[reflect.Type][]func(any)
+}
+
+// NewBus crée un bus sans abonnés
+func NewBus() *Bus {
+	return &Bus{handlers: map[reflect.Type][]func(any){}}
+}
+
+// Subscribe abonne une fonction aux événements de type T
+func Subscribe[T any](b *Bus, handler func(T)) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	b.handlers[t] = append(b.handlers[t], func(e any) { handler(e.(T)) })
+}
+
+// Publish transmet un événement aux abonnés de son type, un bus nil ignore l'événement
+func Publish[T any](b *Bus, e T) {
+	if b == nil {
+		return
+	}
+	for _, handler := range b.handlers[reflect.TypeOf((*T)(nil)).Elem()] {
+		handler(e)
+	}
+}
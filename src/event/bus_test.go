@@ -0,0 +1,28 @@
+package event
+
+import "testing"
+
+func TestBus(t *testing.T) {
+	bus := NewBus()
+	var order []string
+	Subscribe(bus, func(e FoodEaten) { order = append(order, "first") })
+	Subscribe(bus, func(e FoodEaten) { order = append(order, "second") })
+	Subscribe(bus, func(e Died) { order = append(order, "died:"+e.Cause) })
+
+	Publish(bus, FoodEaten{Length: 3})
+	Publish(bus, Died{Cause: "wall"})
+	Publish(bus, SpeedUp{Interval: 4}) // aucun abonné
+
+	want := []string{"first", "second", "died:wall"}
+	if len(order) != len(want) {
+		t.Fatalf("obtenu %v, attendu %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("obtenu %v, attendu %v", order, want)
+		}
+	}
+
+	var nilBus *Bus
+	Publish(nilBus, FoodEaten{}) // ne doit pas paniquer
+}
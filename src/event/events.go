@@ -0,0 +1,72 @@
+package event
+
+import (
+	"image"
+	"time"
+)
+
+// GameStarted est publié au début d'une partie, avec ce qu'il faut pour la rejouer
+type GameStarted struct {
+	Mode       string
+	Difficulty string // identifiant de la difficulté : Facile, Normal ou Difficile
+	Width      int    // dimensions du plateau, en cellules
+	Height     int
+	Seed       int64 // graine du générateur aléatoire du plateau
+	Lives      int
+	Interval   int    // nombre de ticks entre deux déplacements au départ
+	Assist     bool   // serpent ralenti par l'assistance, la partie ne compte pas dans les classements
+	Theme      string // apparence du plateau et du serpent
+	Skin       string
+	Color      int
+}
+
+// Turned est publié quand le serpent change de direction, juste avant de se déplacer
+type Turned struct {
+	Direction int // valeur de board.Direction
+}
+
+// Moved est publié à chaque déplacement du serpent d'une case
+type Moved struct {
+	Head      image.Point
+	Food      image.Point // position de la pomme, avant que le serpent ne la mange
+	Clearance int         // nombre de cases libres entre la tête et le mur ou l'obstacle le plus proche
+}
+
+// FoodEaten est publié quand le serpent mange une pomme
+type FoodEaten struct {
+	Position image.Point
+	Length   int  // longueur du serpent après avoir mangé
+	Detour   bool // vrai si le serpent n'a pas pris le plus court chemin jusqu'à la pomme
+}
+
+// ItemPicked est publié quand le serpent ramasse un objet autre qu'une pomme
+type ItemPicked struct {
+	Item     string
+	Position image.Point
+}
+
+// Died est publié à chaque mort du serpent, qu'elle termine la partie ou lui coûte une vie
+type Died struct {
+	Cause    string // identifiant de la cause : wall, self, obstacle...
+	Position image.Point
+	Length   int
+}
+
+// LifeLost est publié quand une mort coûte une vie sans terminer la partie
+type LifeLost struct {
+	Cause     string
+	LivesLeft int
+}
+
+// SpeedUp est publié quand le serpent accélère
+type SpeedUp struct {
+	Interval int // nouveau nombre de ticks entre deux déplacements
+}
+
+// GameEnded est publié à la fin d'une partie
+type GameEnded struct {
+	Score    int
+	Cause    string
+	Length   int
+	Duration time.Duration
+}
@@ -0,0 +1,43 @@
+package game
+
+import (
+	"snake-go/src/achievement"
+	"snake-go/src/i18n"
+	"snake-go/src/theme"
+	"snake-go/src/ui/widget"
+)
+
+// Galerie des succès d'un profil, les succès débloqués sont mis en valeur
+func (g *Game) buildAchievements() *widget.Panel {
+	if len(g.Profiles.Profiles) == 0 {
+		return g.buildNoProfile(i18n.T("achievements.title"))
+	}
+	p := g.currentShownProfile()
+	palette := theme.Current().Palette
+
+	all := achievement.All()
+	done := 0
+	for _, d := range all {
+		if _, ok := p.Achievements[d.ID]; ok {
+			done++
+		}
+	}
+
+	panel := widget.NewPanel(
+		widget.NewTitle(i18n.T("achievements.title")),
+		g.profileSlider(g.buildAchievements),
+		&widget.Label{Text: i18n.T("achievements.count", done, len(all)), TextSize: 20},
+	)
+	for _, d := range all {
+		line := &widget.Label{Text: i18n.T("achievements.locked", d.Name(), d.Description()), TextSize: 18, Color: palette.Border}
+		if _, ok := p.Achievements[d.ID]; ok {
+			line.Text = i18n.T("achievements.done", d.Name(), d.Description())
+			line.Color = palette.Accent
+		}
+		panel.Add(line)
+	}
+	panel.Add(widget.NewButton(i18n.T("common.back"), g.leaveProfileScreen))
+	panel.Spacing = 12
+	panel.OnBack = g.leaveProfileScreen
+	return panel
+}
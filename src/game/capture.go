@@ -0,0 +1,138 @@
+package game
+
+import (
+	"image"
+	"path/filepath"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"snake-go/src/capture"
+	"snake-go/src/config"
+	"snake-go/src/event"
+	"snake-go/src/i18n"
+)
+
+// Touches des captures, utilisables à tout moment
+const (
+	screenshotKey = ebiten.KeyF12
+	clipKey       = ebiten.KeyF9
+)
+
+// Images des clips
+const (
+	clipInterval = 6                       // une image du clip toutes les clipInterval images du jeu, 10 par seconde
+	clipDelay    = clipInterval * 100 / 60 // durée d'une image du clip, en centièmes de seconde
+	clipWidth    = 480                     // largeur des images du clip, en pixels
+)
+
+// capturer enregistre les captures d'écran et garde les dernières secondes de la partie pour les clips
+type capturer struct {
+	clip       *capture.Clip
+	frame      *ebiten.Image // écran réduit à la taille des images du clip
+	pixels     *image.RGBA   // pixels de frame, ajoutés au clip
+	frames     int           // images dessinées pendant la partie
+	screenshot bool          // capture d'écran demandée, prise à la fin de la prochaine image
+	saved      chan captureResult
+}
+
+// captureResult est une capture enregistrée en arrière-plan, annoncée par une notification
+type captureResult struct {
+	clip bool
+	path string
+	err  error
+}
+
+// Vide le clip au début de chaque partie : un clip ne montre jamais la fin de la partie précédente
+func (c *capturer) subscribe(bus *event.Bus) {
+	event.Subscribe(bus, func(e event.GameStarted) {
+		if c.clip != nil {
+			c.clip.Reset()
+		}
+	})
+}
+
+// Traite les touches des captures et annonce les fichiers enregistrés depuis le tick précédent
+func (g *Game) updateCaptures() {
+	c := &g.captures
+	if c.saved == nil {
+		c.saved = make(chan captureResult, 4)
+	}
+	if inpututil.IsKeyJustPressed(screenshotKey) {
+		c.screenshot = true
+	}
+	if inpututil.IsKeyJustPressed(clipKey) {
+		g.saveClip()
+	}
+
+	for {
+		select {
+		case result := <-c.saved:
+			switch {
+			case result.err != nil:
+				g.Toasts.Push(i18n.T("capture.failed"), result.err.Error())
+			case result.clip:
+				g.Toasts.Push(i18n.T("capture.clip_saved"), filepath.Base(result.path))
+			default:
+				g.Toasts.Push(i18n.T("capture.screenshot_saved"), filepath.Base(result.path))
+			}
+		default:
+			return
+		}
+	}
+}
+
+// Enregistre en arrière-plan un clip des dernières secondes de la partie
+func (g *Game) saveClip() {
+	c := &g.captures
+	if c.clip == nil || c.clip.Len() == 0 {
+		g.Toasts.Push(i18n.T("capture.clip_empty"), "")
+		return
+	}
+	frames, apng := c.clip.Frames(), g.Config.Capture.APNG
+	go func() {
+		path, err := capture.SaveClip(config.CapturesDir(), frames, clipDelay, apng, time.Now())
+		c.saved <- captureResult{clip: true, path: path, err: err}
+	}()
+}
+
+// Prend la capture d'écran demandée et ajoute l'image au clip pendant la partie,
+// à appeler une fois l'image entièrement dessinée
+//
+// screen: l'image finale, après le post-traitement
+func (g *Game) drawCaptures(screen *ebiten.Image) {
+	c := &g.captures
+	bounds := screen.Bounds()
+	if c.screenshot {
+		c.screenshot = false
+		img := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		screen.ReadPixels(img.Pix)
+		go func() {
+			path, err := capture.SavePNG(config.CapturesDir(), img, time.Now())
+			c.saved <- captureResult{path: path, err: err}
+		}()
+	}
+
+	if g.State != Playing {
+		return
+	}
+	if c.frames++; c.frames%clipInterval != 0 {
+		return
+	}
+	if capacity := g.Config.Capture.ClipSeconds * 60 / clipInterval; c.clip == nil || c.clip.Cap() != capacity {
+		c.clip = capture.NewClip(capacity)
+	}
+	height := max(bounds.Dy()*clipWidth/bounds.Dx(), 1)
+	if c.frame == nil || c.frame.Bounds().Dy() != height {
+		c.frame = ebiten.NewImage(clipWidth, height)
+		c.pixels = image.NewRGBA(image.Rect(0, 0, clipWidth, height))
+		c.clip.Reset() // les images d'un clip ont toutes la même taille
+	}
+	c.frame.Clear()
+	opts := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	opts.GeoM.Scale(float64(clipWidth)/float64(bounds.Dx()), float64(height)/float64(bounds.Dy()))
+	c.frame.DrawImage(screen, opts)
+	c.frame.ReadPixels(c.pixels.Pix)
+	c.clip.Add(c.pixels)
+}
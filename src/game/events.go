@@ -0,0 +1,117 @@
+package game
+
+import (
+	"time"
+
+	"snake-go/src/achievement"
+	"snake-go/src/audio"
+	"snake-go/src/config"
+	"snake-go/src/event"
+	"snake-go/src/i18n"
+	"snake-go/src/profile"
+)
+
+// Bus des événements de la partie, créé au premier appel avec ses abonnés
+func (g *Game) events() *event.Bus {
+	if g.bus == nil {
+		g.bus = event.NewBus()
+		g.subscribe(g.bus)
+	}
+	return g.bus
+}
+
+// Abonne les parties du jeu aux événements, dans l'ordre où elles doivent les recevoir :
+// l'état de la partie, les statistiques et le classement, les succès, l'affichage, les effets visuels et les captures, les sons puis l'enregistrement
+func (g *Game) subscribe(bus *event.Bus) {
+	// état de la partie
+	event.Subscribe(bus, func(e event.FoodEaten) {
+		g.Score++
+		g.Run.Apples++
+		if e.Detour {
+			g.Run.Detours++
+		}
+	})
+	event.Subscribe(bus, func(e event.Moved) { g.Run.Distance++ })
+	event.Subscribe(bus, func(e event.Died) {
+		g.Run.Longest = max(g.Run.Longest, e.Length)
+		g.Run.Deaths = append(g.Run.Deaths, e.Cause)
+	})
+
+	// statistiques du profil et classement, dont les parties avec l'assistance à la vitesse sont exclues
+	event.Subscribe(bus, func(e event.GameEnded) {
+		name := ""
+		if g.Profile != nil {
+			name = g.Profile.Name
+		}
+		if !g.assisted {
+			g.AddScore(e.Score, name)
+		}
+		if g.Profile == nil {
+			return
+		}
+		g.Profile.RecordGame(profile.GameResult{
+			Mode:       g.Mode,
+			Difficulty: g.Difficulty.id(),
+			Score:      e.Score,
+			Apples:     g.Run.Apples,
+			Distance:   g.Run.Distance,
+			Length:     g.Run.Longest,
+			Duration:   e.Duration,
+			Deaths:     g.Run.Deaths,
+			Assisted:   g.assisted,
+		})
+		g.saveProfiles()
+	})
+
+	// succès
+	event.Subscribe(bus, func(e event.FoodEaten) {
+		if g.Profile == nil {
+			return
+		}
+		g.checkAchievements(achievement.FoodEaten, map[string]int{
+			"length":       e.Length,
+			"score":        g.Score,
+			"apples_total": g.Profile.Stats.ApplesEaten + g.Run.Apples,
+			"lives_lost":   len(g.Run.Deaths), // vies perdues jusqu'ici, la dernière mort termine la partie
+		})
+	})
+	event.Subscribe(bus, func(e event.GameEnded) {
+		if g.Profile == nil {
+			return
+		}
+		g.checkAchievements(achievement.GameEnded, map[string]int{
+			"score":            e.Score,
+			"apples":           g.Run.Apples,
+			"length":           g.Run.Longest,
+			"lives_lost":       len(g.Run.Deaths) - 1,
+			"detours":          g.Run.Detours,
+			"duration_seconds": int(e.Duration.Seconds()),
+			"games_played":     g.Profile.GamesPlayed,
+		})
+	})
+
+	g.hud.subscribe(bus)
+	g.effects.Subscribe(bus)
+	g.captures.subscribe(bus)
+	audio.Subscribe(bus)
+	g.replays.Dir = config.ReplaysDir()
+	g.replays.Subscribe(bus)
+}
+
+// Débloque les succès du profil remplis par un événement de la partie, et les annonce par une notification
+//
+// trigger: le déclencheur de l'événement (achievement.FoodEaten, achievement.GameEnded)
+// values: les valeurs testées par les conditions des succès
+func (g *Game) checkAchievements(trigger string, values map[string]int) {
+	if g.Profile.Achievements == nil {
+		g.Profile.Achievements = map[string]time.Time{}
+	}
+	e := achievement.Event{Trigger: trigger, Mode: g.Mode, Difficulty: g.Difficulty.id(), Values: values}
+	unlocked := achievement.Process(e, g.Profile.Achievements)
+	for _, d := range unlocked {
+		g.Toasts.Push(i18n.T("achievements.unlocked"), d.Name())
+	}
+	if len(unlocked) > 0 {
+		g.saveProfiles()
+	}
+}
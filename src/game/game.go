@@ -1,19 +1,28 @@
 package game
 
 import (
-	"image/color"
+	"errors"
+	"log"
+	"math/rand"
 	"sort"
-	"strconv"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text"
-	"golang.org/x/image/font/basicfont"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 
 	"snake-go/src/audio"
+	"snake-go/src/board"
+	"snake-go/src/config"
 	"snake-go/src/constants"
-	"snake-go/src/resources"
+	"snake-go/src/event"
+	"snake-go/src/i18n"
+	"snake-go/src/postfx"
+	"snake-go/src/profile"
+	"snake-go/src/replay"
+	"snake-go/src/theme"
 	"snake-go/src/ui"
+	"snake-go/src/ui/widget"
+	"snake-go/src/vfx"
 )
 
 // Etats dans le jeu, on peut être dans le menu, en train de jouer, en train de choisir le mode de jeu, etc.
@@ -21,12 +30,21 @@ type GameState int
 
 const (
 	Menu GameState = iota
-	NameInput
+	ProfileSelection
+	ProfileEdit
 	ModeSelection
 	DifficultySelection
 	Playing
 	GameOver
 	Credits
+	Settings
+	Statistics
+	Achievements
+	AudioSettings
+	EffectsSettings
+	PostFXSettings
+	AccessSettings
+	CaptureSettings
 )
 
 // Déclaration des niveaux de difficulté
@@ -38,11 +56,27 @@ const (
 	Difficile
 )
 
-// Variables globales
-var (
-	currentSelection Difficulty
-	lastEnterPress   time.Time
-)
+// identifiant de la difficulté, utilisé comme clé des meilleurs scores des profils
+func (d Difficulty) id() string {
+	switch d {
+	case Facile:
+		return "Facile"
+	case Difficile:
+		return "Difficile"
+	}
+	return "Normal"
+}
+
+// nom traduit de la difficulté
+func (d Difficulty) name() string {
+	switch d {
+	case Facile:
+		return i18n.T("difficulty.easy")
+	case Difficile:
+		return i18n.T("difficulty.hard")
+	}
+	return i18n.T("difficulty.normal")
+}
 
 type Score struct {
 	Value int
@@ -57,53 +91,132 @@ type Game struct {
 	State             GameState
 	UpdateCount       int
 	UpdateInterval    int
-	ScoreAdded        bool
-	PlayerName        string
+	Profiles          *profile.Store   // profils enregistrés
+	Profile           *profile.Profile // profil du joueur, choisi avant chaque partie
 	Difficulty        Difficulty
 	Mode              string
 	Lives             int
 	LastSpeedIncrease int
+	StartTime         time.Time
+	Death             GameOverDetails
+	Run               RunStats        // compteurs de la partie en cours, ajoutés aux statistiques du profil à la fin
+	Toasts            ui.Toasts       // notifications affichées par-dessus le jeu (succès débloqués...)
+	Config            config.Config   // paramètres sauvegardés (dimensions du plateau, thème...)
+	Perf              *ui.PerfOverlay // mesures de performance affichées à l'écran, nil si désactivées
+	screenWidth       int             // taille de l'écran logique, mise à jour par Layout
+	screenHeight      int
+	menu              *widget.Panel // écran de menu affiché, construit pour l'état menuState
+	menuState         GameState
+	narrated          string           // dernière description lue par la narration des menus
+	editedProfile     *profile.Profile // profil modifié sur l'écran ProfileEdit, nil pour en créer un
+	bus               *event.Bus       // événements de la partie, créé avec ses abonnés par events()
+	hud               hud
+	effects           vfx.Effects   // particules, tremblement et flash de la partie en cours
+	dying             int           // ticks de ralenti restants avant l'écran de fin de partie, 0 hors ralenti
+	boardFrame        *ebiten.Image // image hors écran du plateau, décalée pendant un tremblement
+	postfx            postfx.Pipeline
+	replays           replay.Recorder
+	captures          capturer // captures d'écran et clips des dernières secondes de la partie
+	seed              int64    // graine du plateau de la partie en cours
+	assisted          bool     // partie jouée avec l'assistance à la vitesse, hors des classements
+	rng               *rand.Rand
+	shownProfile      *profile.Profile // profil affiché sur les écrans des statistiques et des succès
+	nowPlaying        *audio.Track     // musique annoncée par la dernière notification
+	quitRequested     bool
+}
+
+// Détails de la fin de partie, affichés sur l'écran de game over
+type GameOverDetails struct {
+	Cause       board.DeathCause
+	Position    board.Position
+	FinalLength int
+	PlayTime    time.Duration
+	FinalBoard  *ebiten.Image // image figée de la grille au moment de la mort
+}
+
+// Compteurs d'une partie, toutes vies confondues
+type RunStats struct {
+	Apples   int
+	Distance int      // nombre de cases parcourues
+	Longest  int      // longueur maximale atteinte par le serpent
+	Detours  int      // pommes qui n'ont pas été mangées par le plus court chemin
+	Deaths   []string // identifiants des causes de chaque mort
 }
 
 // Fonction principale de mise à jour du jeu, appel les méthodes selon l'état du jeu
 func (g *Game) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyF11) {
+		ebiten.SetFullscreen(!ebiten.IsFullscreen())
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF10) {
+		g.toggleMute()
+	}
+	g.updateCaptures()
+
+	audio.PlayMusicFor(g.musicState())
+	audio.Update()
+	if track := audio.CurrentTrack(); track != g.nowPlaying {
+		g.nowPlaying = track
+		if track != nil {
+			g.Toasts.Push(i18n.T("music.now_playing"), track.Label())
+		}
+	}
+	g.Toasts.Update()
+	g.postfx.Options = g.Config.PostFX
+	g.postfx.ColorVision = g.Config.Access.ColorVision
+	g.Toasts.Still = g.Config.Access.ReducedMotion
+	audio.SetCues(g.Config.Access.AudioCues)
+	g.postfx.Update(g.State == GameOver)
+
 	switch g.State {
-	case Menu:
-		return g.updateMenu()
-	case NameInput:
-		return g.updateNameInput()
-	case ModeSelection:
-		return g.updateModeSelection()
-	case DifficultySelection:
-		return g.updateDifficultySelection()
 	case Playing:
 		return g.updatePlaying()
-	case GameOver:
-		return g.updateGameOver()
-	case Credits:
-		return g.updateCredits()
 	}
-	return nil
+	return g.updateScreen()
 }
 
 // Mise à jour de l'état de jeu pendant la partie
 func (g *Game) updatePlaying() error {
+	g.GridManager.Animate()
+	if g.dying > 0 {
+		g.effects.Update(vfx.SlowMotionScale)
+		if g.dying--; g.dying == 0 {
+			g.endGame()
+		}
+		return nil
+	}
+	g.effects.Update(1)
+
 	g.UpdateCount++
 	if g.UpdateCount >= g.UpdateInterval {
 		if g.Score > 0 && g.Score%5 == 0 && g.Score != g.LastSpeedIncrease { // Ma vitesse sera augmentée à chaque fois que 5 pommes sont mangées
-			g.UpdateInterval = max(3, g.UpdateInterval-1) // Réduire l'intervalle de mise à jour mais pas en dessous de 3
-			g.LastSpeedIncrease = g.Score                 // Permet d'enregistrer le score où la vitesse a été augmentée comme ça on ne l'augmente qu'une seule fois par 5 points
+			g.UpdateInterval = max(board.MinInterval(g.assisted), g.UpdateInterval-1) // Réduire l'intervalle de mise à jour mais pas en dessous de 3 (4 avec l'assistance)
+			g.LastSpeedIncrease = g.Score                                             // Permet d'enregistrer le score où la vitesse a été augmentée comme ça on ne l'augmente qu'une seule fois par 5 points
+			event.Publish(g.events(), event.SpeedUp{Interval: g.UpdateInterval})
 		}
 		err := g.GridManager.Update(g)
 		if err != nil {
+			var death *board.DeathError
+			if !errors.As(err, &death) {
+				return err
+			}
 			if g.Lives > 1 { // Si on a plus d'une vie (dans le mode challenge), on perd une vie et on recommence tout en gardant le score
 				g.Lives--
-				g.GridManager = NewGridWithObstacles(constants.CellSize, g.Difficulty)
+				g.GridManager = g.newGrid()
+				event.Publish(g.events(), event.LifeLost{Cause: death.Cause.ID(), LivesLeft: g.Lives})
 			} else {
-				g.State = GameOver
-				g.ScoreAdded = false
-				audio.LoseSoundPlayer.Rewind()
-				audio.LoseSoundPlayer.Play()
+				g.Death = GameOverDetails{
+					Cause:       death.Cause,
+					Position:    death.Position,
+					FinalLength: g.GridManager.SnakeLength(),
+					PlayTime:    time.Since(g.StartTime),
+					FinalBoard:  g.captureBoard(),
+				}
+				if g.effects.Options.SlowMotion {
+					g.dying = vfx.SlowMotionTicks // les effets de la mort se jouent au ralenti avant la fin de partie
+				} else {
+					g.endGame()
+				}
 			}
 		}
 		g.UpdateCount = 0
@@ -111,36 +224,38 @@ func (g *Game) updatePlaying() error {
 	return nil
 }
 
-// Mise à jour de l'état de jeu lors du game over
-func (g *Game) updateGameOver() error {
-	if !g.ScoreAdded {
-		g.AddScore(g.Score, g.PlayerName)
-		g.ScoreAdded = true
-	}
-	if ebiten.IsKeyPressed(ebiten.KeyR) {
-		g.startGame()
-		g.ScoreAdded = false
-		audio.BackgroundPlayer.Rewind()
-		audio.BackgroundPlayer.Play()
-	} else if ebiten.IsKeyPressed(ebiten.KeyEnter) {
-		if time.Since(lastEnterPress) > 500*time.Millisecond {
-			g.State = Menu // Retourau menu
-			lastEnterPress = time.Now()
-			audio.BackgroundPlayer.Rewind()
-			audio.BackgroundPlayer.Play()
-		}
+// Fin de la partie, après le ralenti de la mort du serpent
+func (g *Game) endGame() {
+	g.State = GameOver
+	event.Publish(g.events(), event.GameEnded{
+		Score:    g.Score,
+		Cause:    g.Death.Cause.ID(),
+		Length:   g.Death.FinalLength,
+		Duration: g.Death.PlayTime,
+	})
+}
+
+// Moment du jeu dont on joue les musiques
+func (g *Game) musicState() audio.MusicState {
+	switch g.State {
+	case Playing:
+		return audio.PlayingMusic
+	case GameOver:
+		return audio.GameOverMusic
 	}
-	return nil
+	return audio.MenuMusic
 }
 
-// Mise à jour de l'état de jeu lors des crédits, ça permet de revenir au menu principal
-func (g *Game) updateCredits() error {
-	if ebiten.IsKeyPressed(ebiten.KeyEscape) {
-		g.State = Menu
-		audio.BackgroundPlayer.Rewind()
-		audio.BackgroundPlayer.Play()
+// Coupe ou rétablit le son et enregistre ce choix dans la configuration
+func (g *Game) toggleMute() {
+	g.Config.Muted = !g.Config.Muted
+	audio.SetMuted(g.Config.Muted)
+	if err := g.Config.Save(); err != nil {
+		log.Printf("Impossible d'enregistrer la configuration: %v", err)
+	}
+	if g.State == AudioSettings {
+		g.menu = nil // le bouton de l'écran des paramètres suit le nouveau réglage
 	}
-	return nil
 }
 
 // Initialisation des paramètres de jeu selon la difficulté choisie
@@ -165,20 +280,58 @@ func (g *Game) startGame() {
 		g.Lives = 1
 	}
 
-	// Initialisation de la grille en fonction du mode de jeu pour savoir si il y a des obstacles ou non
-	if g.Mode == "Challenge" {
-		g.GridManager = NewGridWithObstacles(constants.CellSize, g.Difficulty)
-	} else {
-		g.GridManager = NewGrid(constants.CellSize)
+	g.assisted = g.Config.Access.AssistSpeed
+	if g.assisted { // le serpent va moins vite, la partie ne compte pas dans les classements
+		g.UpdateInterval = g.UpdateInterval * board.AssistSlowdown / 100
 	}
 
+	g.seed = time.Now().UnixNano()
+	g.rng = rand.New(rand.NewSource(g.seed))
+	g.GridManager = g.newGrid()
+
 	// Réinitialisation des autres paramètres de jeu
 	g.Score = 0
 	g.State = Playing
 	g.UpdateCount = 0
 	g.LastSpeedIncrease = 0
-	audio.BackgroundPlayer.Rewind()
-	audio.BackgroundPlayer.Play()
+	g.StartTime = time.Now()
+	g.Death = GameOverDetails{}
+	g.Run = RunStats{}
+	g.dying = 0
+	g.effects.Options = g.Config.Effects
+	if g.Config.Access.ReducedMotion {
+		g.effects.Options = config.Effects{} // ni tremblement, ni flash, ni particules, ni ralenti
+	}
+	g.menu = nil
+	ebiten.SetCursorShape(ebiten.CursorShapeDefault)
+
+	started := event.GameStarted{
+		Mode:       g.Mode,
+		Difficulty: g.Difficulty.id(),
+		Width:      g.Config.BoardWidth,
+		Height:     g.Config.BoardHeight,
+		Seed:       g.seed,
+		Lives:      g.Lives,
+		Interval:   g.UpdateInterval,
+		Assist:     g.assisted,
+		Theme:      theme.Current().ID,
+	}
+	if g.Profile != nil {
+		started.Skin, started.Color = g.Profile.Skin, g.Profile.Color
+	}
+	event.Publish(g.events(), started)
+}
+
+// Création de la grille en fonction du mode de jeu pour savoir si il y a des obstacles ou non,
+// le serpent prend l'apparence choisie dans le profil du joueur
+func (g *Game) newGrid() *Grid {
+	grid := &Grid{board.NewGame(g.Mode, g.Difficulty.id(), g.Config.BoardWidth, g.Config.BoardHeight, g.rng)}
+	grid.Access = g.Config.Access
+	if g.Profile != nil {
+		grid.Skin = theme.Find(g.Profile.Skin)
+		grid.Tint = g.Profile.Tint()
+	}
+	return grid
 }
 
 // Ajout d'un nouveau score à la liste des scores
@@ -195,53 +348,102 @@ func (g *Game) AddScore(newScore int, newName string) {
 }
 
 // Dessin des éléments à l'écran selon l'état du jeu
-func (g *Game) Draw(screen *ebiten.Image) {
-	if resources.BackgroundImage != nil {
+func (g *Game) Draw(final *ebiten.Image) {
+	screen := g.postfx.Begin(final) // les shaders s'appliquent à toute l'image, sauf aux mesures de performance
+	currentTheme := theme.Current()
+	if background := ui.Background(currentTheme); background != nil {
 		opts := &ebiten.DrawImageOptions{}
-		opts.GeoM.Scale(float64(constants.ScreenWidth)/float64(resources.BackgroundImage.Bounds().Dx()), float64(constants.ScreenHeight)/float64(resources.BackgroundImage.Bounds().Dy()))
-		screen.DrawImage(resources.BackgroundImage, opts)
+		opts.GeoM.Scale(float64(screen.Bounds().Dx())/float64(background.Bounds().Dx()), float64(screen.Bounds().Dy())/float64(background.Bounds().Dy()))
+		screen.DrawImage(background, opts)
+	} else {
+		screen.Fill(currentTheme.Palette.Screen)
 	}
 
 	switch g.State {
-	case Menu:
-		ui.RenderMenu(screen)
-	case NameInput:
-		ui.RenderNameInput(screen, g.PlayerName)
-	case ModeSelection:
-		ui.RenderModeSelection(screen)
-	case DifficultySelection:
-		ui.RenderDifficultySelection(screen, int(currentSelection), convertScores(g.Scores))
 	case Playing:
-		g.GridManager.Draw(screen)
-		text.Draw(screen, "Score: "+strconv.Itoa(g.Score), basicfont.Face7x13, 10, 20, color.Black)
-		g.drawLives(screen)
+		g.drawBoard(screen)
+		g.effects.DrawFlash(screen)
+		g.hud.draw(screen, g.effects.ScoreScale())
 	case GameOver:
-		ui.RenderGameOver(screen, g.Score, convertScores(g.Scores))
-	case Credits:
-		ui.RenderCredits(screen)
+		ui.RenderGameOver(screen, g.gameOverSummary(), convertScores(g.Scores))
+		g.drawScreen(screen)
+	default:
+		g.drawScreen(screen)
+	}
+
+	g.Toasts.Draw(screen)
+	g.postfx.End(final)
+	g.drawCaptures(final)
+	if g.Perf != nil {
+		g.Perf.Draw(final)
 	}
 }
 
+// L'écran logique suit la taille de la fenêtre, le plateau et les menus se placent ensuite selon cette taille
+// En plein écran il garde le format 16:9 et ebiten ajoute des bandes noires autour
+// La taille de l'interface agrandit les polices et les widgets, pas l'écran logique
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return constants.ScreenWidth, constants.ScreenHeight
+	if ebiten.IsFullscreen() {
+		outsideWidth, outsideHeight = ui.Letterbox(outsideWidth, outsideHeight)
+	}
+	g.screenWidth, g.screenHeight = ui.LogicalSize(outsideWidth, outsideHeight)
+	ui.SetScale(float64(g.Config.Access.UIScale) / 100)
+	return g.screenWidth, g.screenHeight
 }
 
-// Dessin des vies restantes à l'écran
-func (g *Game) drawLives(screen *ebiten.Image) {
-	if resources.HeartImage == nil {
-		return
+// Dessin du plateau et des particules, décalés pendant un tremblement de l'écran
+func (g *Game) drawBoard(screen *ebiten.Image) {
+	dx, dy := g.effects.ShakeOffset()
+	target := screen
+	if dx != 0 || dy != 0 {
+		bounds := screen.Bounds()
+		if g.boardFrame == nil || g.boardFrame.Bounds() != bounds {
+			g.boardFrame = ebiten.NewImage(bounds.Dx(), bounds.Dy())
+		}
+		g.boardFrame.Clear()
+		target = g.boardFrame
 	}
 
-	textColor := color.RGBA{255, 0, 0, 255}
-	fontFace := basicfont.Face7x13
-	text.Draw(screen, "Vies :", fontFace, 10, 50, textColor)
+	g.GridManager.Draw(target)
+	layout := g.GridManager.Layout(target.Bounds().Dx(), target.Bounds().Dy())
+	g.effects.DrawParticles(target, layout)
+
+	food := g.GridManager.Food()
+	x, y := layout.CellPosition(food.X, food.Y)
+	g.postfx.Highlight(x+dx, y+dy, float64(layout.CellSize), theme.Current().Palette.Accent)
 
-	for i := 0; i < g.Lives; i++ {
+	if target != screen {
 		opts := &ebiten.DrawImageOptions{}
-		scale := 0.02
-		opts.GeoM.Scale(scale, scale)
-		opts.GeoM.Translate(float64(55+i*int(float64(resources.HeartImage.Bounds().Dx())*scale)), 40)
-		screen.DrawImage(resources.HeartImage, opts)
+		opts.GeoM.Translate(dx, dy)
+		screen.DrawImage(target, opts)
+	}
+}
+
+// captureBoard dessine la grille dans une image hors écran pour garder une image figée du plateau final
+// Retourne l'image de la grille, bordure comprise
+func (g *Game) captureBoard() *ebiten.Image {
+	width, height := max(g.screenWidth, 1), max(g.screenHeight, 1)
+	frame := ebiten.NewImage(width, height)
+	g.GridManager.Draw(frame)
+
+	layout := g.GridManager.Layout(width, height)
+	margin := max(layout.CellSize, constants.BorderThickness) // la collision avec un mur est dessinée en dehors de la grille
+	return frame.SubImage(layout.Rect(margin)).(*ebiten.Image)
+}
+
+// Conversion des détails de fin de partie pour affichage
+func (g *Game) gameOverSummary() ui.GameOverSummary {
+	applesPerMinute := 0.0
+	if minutes := g.Death.PlayTime.Minutes(); minutes > 0 {
+		applesPerMinute = float64(g.Score) / minutes
+	}
+	return ui.GameOverSummary{
+		Score:           g.Score,
+		Cause:           g.Death.Cause.String(),
+		Length:          i18n.N("gameover.length_value", g.Death.FinalLength),
+		PlayTime:        g.Death.PlayTime,
+		ApplesPerMinute: applesPerMinute,
+		Board:           g.Death.FinalBoard,
 	}
 }
 
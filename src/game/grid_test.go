@@ -0,0 +1,55 @@
+package game
+
+import (
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"snake-go/src/board"
+	"snake-go/src/constants"
+	"snake-go/src/resources"
+	"snake-go/src/theme"
+)
+
+// testGame lance les tests dans une image d'ebiten, pour que les images puissent être dessinées
+type testGame struct {
+	m    *testing.M
+	code int
+}
+
+func (g *testGame) Update() error {
+	g.code = g.m.Run()
+	return ebiten.Termination
+}
+
+func (g *testGame) Draw(screen *ebiten.Image) {}
+
+func (g *testGame) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return constants.ScreenWidth, constants.ScreenHeight
+}
+
+func TestMain(m *testing.M) {
+	g := &testGame{m: m, code: 1}
+	if err := ebiten.RunGame(g); err != nil {
+		panic(err)
+	}
+	os.Exit(g.code)
+}
+
+// Coût d'une image du plateau : bordure, fond, serpent, pomme et obstacles, sans créer d'image
+func BenchmarkGridDraw(b *testing.B) {
+	resources.Init("")
+	theme.Init("", "classic")
+	screen := ebiten.NewImage(constants.ScreenWidth, constants.ScreenHeight)
+	defer screen.Dispose()
+	grid := &Grid{board.NewGame(board.ChallengeMode, Normal.id(), 20, 20, rand.New(rand.NewSource(1)))}
+	grid.Draw(screen) // crée les panneaux en cache
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		grid.Animate()
+		grid.Draw(screen)
+	}
+}
@@ -1,9 +1,17 @@
 package game
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"snake-go/src/board"
+)
 
 // GridManager définit les méthodes nécessaires pour gérer et dessiner une grille.
 type GridManager interface {
 	Update(game *Game) error
 	Draw(screen *ebiten.Image)
+	Animate()
+	SnakeLength() int
+	Food() board.Position
+	Layout(screenWidth, screenHeight int) board.Layout
 }
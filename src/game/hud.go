@@ -0,0 +1,63 @@
+package game
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font/basicfont"
+
+	"snake-go/src/event"
+	"snake-go/src/i18n"
+	"snake-go/src/resources"
+	"snake-go/src/theme"
+	"snake-go/src/ui"
+)
+
+// hud affiche le score et les vies pendant la partie, il est tenu à jour par les événements
+type hud struct {
+	score  int
+	lives  int
+	assist bool // la partie est jouée avec l'assistance à la vitesse
+}
+
+func (h *hud) subscribe(bus *event.Bus) {
+	event.Subscribe(bus, func(e event.GameStarted) {
+		h.score, h.lives, h.assist = 0, e.Lives, e.Assist
+	})
+	event.Subscribe(bus, func(e event.FoodEaten) { h.score++ })
+	event.Subscribe(bus, func(e event.LifeLost) { h.lives = e.LivesLeft })
+}
+
+// Dessin du score et des vies restantes à l'écran, agrandis à la taille de l'interface
+//
+// scoreScale: l'agrandissement du score, supérieur à 1 quand il pulse après une pomme
+func (h *hud) draw(screen *ebiten.Image, scoreScale float64) {
+	s := ui.Scale()
+	write := func(msg string, x, y, textScale float64, c color.Color) {
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Scale(textScale*s, textScale*s)
+		opts.GeoM.Translate(x*s, y*s)
+		opts.ColorScale.ScaleWithColor(c)
+		text.DrawWithOptions(screen, msg, basicfont.Face7x13, opts)
+	}
+	write(i18n.T("hud.score", h.score), 10, 20, scoreScale, theme.Current().Palette.Text)
+	if h.assist {
+		write(i18n.T("hud.assist"), 10, 80, 1, theme.Current().Palette.Text)
+	}
+	heart := ui.Image(resources.HeartImage)
+	if heart == nil {
+		return
+	}
+
+	textColor := color.RGBA{255, 0, 0, 255}
+	write(i18n.N("hud.lives", h.lives), 10, 50, 1, textColor)
+
+	for i := 0; i < h.lives; i++ {
+		opts := &ebiten.DrawImageOptions{}
+		scale := 0.02 * s
+		opts.GeoM.Scale(scale, scale)
+		opts.GeoM.Translate(55*s+float64(i)*float64(heart.Bounds().Dx())*scale, 40*s)
+		screen.DrawImage(heart, opts)
+	}
+}
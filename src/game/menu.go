@@ -1,83 +1,229 @@
 package game
 
 import (
-	"os"
-	"time"
-
-	"snake-go/src/audio"
-	"snake-go/src/input"
+	"errors"
+	"image"
+	"log"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
-)
 
-var (
-	lastMenuUpdate time.Time
+	"snake-go/src/audio"
+	"snake-go/src/i18n"
+	"snake-go/src/resources"
+	"snake-go/src/speech"
+	"snake-go/src/theme"
+	"snake-go/src/ui"
+	"snake-go/src/ui/widget"
 )
 
-// Cette fonction gère les input dans le menu principal
-func (g *Game) updateMenu() error {
-	if ebiten.IsKeyPressed(ebiten.Key1) {
-		g.State = NameInput
-	}
-	if ebiten.IsKeyPressed(ebiten.Key2) {
-		g.State = Credits
+// Met à jour l'écran de menu correspondant à l'état du jeu
+// L'écran est reconstruit à chaque changement d'état pour afficher des informations à jour (scores, paramètres...)
+func (g *Game) updateScreen() error {
+	if g.menu == nil || g.menuState != g.State {
+		g.menu = g.buildScreen(g.State)
+		g.menuState = g.State
 	}
-	if ebiten.IsKeyPressed(ebiten.Key3) {
-		os.Exit(0)
+	g.fitScale()
+	g.menu.Layout(image.Rect(0, 0, g.screenWidth, g.screenHeight))
+	g.menu.Update(widget.ReadInput())
+	g.narrate()
+
+	if g.quitRequested {
+		return ebiten.Termination
 	}
 	return nil
 }
 
-// Gère la saisie du nom du joueur
-func (g *Game) updateNameInput() error {
-	if ebiten.IsKeyPressed(ebiten.KeyEnter) && len(g.PlayerName) > 0 {
-		if time.Since(lastEnterPress) > 500*time.Millisecond {
-			g.State = ModeSelection
-			lastEnterPress = time.Now()
-		}
+// Annonce le widget qui a le focus quand il change ou que sa valeur change, si la narration est activée :
+// un bip puis sa description lue à voix haute
+func (g *Game) narrate() {
+	if !g.Config.Access.Narration {
+		g.narrated = ""
+		return
+	}
+	widget.ToggleStates = [2]string{i18n.T("narration.off"), i18n.T("narration.on")}
+	description := g.menu.Describe()
+	if description == g.narrated {
+		return
+	}
+	g.narrated = description
+	audio.PlaySound(audio.FocusSound)
+	if err := speech.Say(description, g.Config.Language); err != nil && !errors.Is(err, speech.ErrUnavailable) {
+		log.Printf("Impossible de lire %q à voix haute: %v", description, err)
 	}
-	input.HandleNameInput(&g.PlayerName)
-	return nil
 }
 
-// Gère la sélection du mode de jeu
-func (g *Game) updateModeSelection() error {
-	if ebiten.IsKeyPressed(ebiten.Key1) {
-		g.Mode = "Classique"
-		g.State = DifficultySelection
+// Dessine l'écran de menu courant
+func (g *Game) drawScreen(screen *ebiten.Image) {
+	if g.menu == nil || g.menuState != g.State {
+		return
 	}
-	if ebiten.IsKeyPressed(ebiten.Key2) {
-		g.Mode = "Challenge"
-		g.State = DifficultySelection
-	}
-	return nil
+	g.fitScale()
+	g.menu.Layout(screen.Bounds())
+	g.menu.Draw(screen, widget.State{})
 }
 
-// Gère la sélection de la difficulté
-func (g *Game) updateDifficultySelection() error {
-	if time.Since(lastMenuUpdate) > 200*time.Millisecond {
-		if ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
-			if currentSelection > Facile {
-				currentSelection--
-			}
-			lastMenuUpdate = time.Now()
+// Réduit la taille de l'interface si l'écran de menu courant ne tient pas dans l'écran logique à la taille choisie :
+// les panneaux ne défilent pas, un écran trop grand serait coupé
+// A 100 %, tous les écrans tiennent dans l'écran logique minimal
+func (g *Game) fitScale() {
+	scale := float64(g.Config.Access.UIScale) / 100
+	ui.SetScale(scale)
+	if g.State == GameOver {
+		// les boutons suivent le panneau de fin de partie, réduit si l'écran est trop petit
+		ui.SetScale(ui.GameOverScale(g.screenWidth, g.screenHeight))
+		return
+	}
+	width, height := g.menu.Size()
+	fit := min(scale*float64(g.screenWidth)/float64(max(width, 1)), scale*float64(g.screenHeight)/float64(max(height, 1)))
+	if fit >= scale {
+		return
+	}
+	// par pas de 5 %, pour ne pas créer une police à chaque redimensionnement ; les polices ne grandissent pas
+	// tout à fait en proportion, la taille est encore réduite si l'écran déborde
+	ui.SetScale(math.Floor(fit*20) / 20)
+	for ui.Scale() > 1 {
+		if width, height := g.menu.Size(); width <= g.screenWidth && height <= g.screenHeight {
+			return
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
-			if currentSelection < Difficile {
-				currentSelection++
-			}
-			lastMenuUpdate = time.Now()
+		ui.SetScale(ui.Scale() - 0.05)
+	}
+}
+
+// Construit l'écran d'un état du jeu
+//
+// state: l'état dont on construit l'écran
+// Retourne le panneau de l'écran
+func (g *Game) buildScreen(state GameState) *widget.Panel {
+	switch state {
+	case ProfileSelection:
+		return g.buildProfileSelection()
+	case ProfileEdit:
+		return g.buildProfileEdit()
+	case ModeSelection:
+		return g.buildModeSelection()
+	case DifficultySelection:
+		return g.buildDifficultySelection()
+	case Credits:
+		return g.buildCredits()
+	case Settings:
+		return g.buildSettings()
+	case AudioSettings:
+		return g.buildAudioSettings()
+	case EffectsSettings:
+		return g.buildEffectsSettings()
+	case PostFXSettings:
+		return g.buildPostFXSettings()
+	case AccessSettings:
+		return g.buildAccessSettings()
+	case CaptureSettings:
+		return g.buildCaptureSettings()
+	case Statistics:
+		return g.buildStatistics()
+	case Achievements:
+		return g.buildAchievements()
+	case GameOver:
+		return g.buildGameOver()
+	}
+	return g.buildMainMenu()
+}
+
+// Menu principal, les touches 1 à 6 sont des raccourcis vers chaque entrée
+func (g *Game) buildMainMenu() *widget.Panel {
+	return widget.NewPanel(
+		widget.NewTitle(i18n.T("menu.title")),
+		widget.NewButton(i18n.T("menu.start"), func() { g.State = ProfileSelection }).WithShortcut(ebiten.Key1),
+		widget.NewButton(i18n.T("menu.settings"), func() { g.State = Settings }).WithShortcut(ebiten.Key2),
+		widget.NewButton(i18n.T("menu.statistics"), func() { g.State = Statistics }).WithShortcut(ebiten.Key3),
+		widget.NewButton(i18n.T("menu.achievements"), func() { g.State = Achievements }).WithShortcut(ebiten.Key4),
+		widget.NewButton(i18n.T("menu.credits"), func() { g.State = Credits }).WithShortcut(ebiten.Key5),
+		widget.NewButton(i18n.T("menu.quit"), func() { g.quitRequested = true }).WithShortcut(ebiten.Key6),
+	)
+}
+
+// Sélection du mode de jeu
+func (g *Game) buildModeSelection() *widget.Panel {
+	chooseMode := func(mode string) func() {
+		return func() {
+			g.Mode = mode
+			g.State = DifficultySelection
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyEnter) {
-			if time.Since(lastEnterPress) > 500*time.Millisecond {
-				g.State = Playing
-				g.Difficulty = currentSelection
-				g.startGame()
-				lastEnterPress = time.Now()
-				audio.BackgroundPlayer.Rewind()
-				audio.BackgroundPlayer.Play()
-			}
+	}
+
+	panel := widget.NewPanel(
+		widget.NewTitle(i18n.T("mode.title")),
+		widget.NewButton(i18n.T("mode.classic"), chooseMode("Classique")).WithShortcut(ebiten.Key1),
+		widget.NewButton(i18n.T("mode.challenge"), chooseMode("Challenge")).WithShortcut(ebiten.Key2),
+		widget.NewButton(i18n.T("common.back"), func() { g.State = ProfileSelection }),
+	)
+	panel.OnBack = func() { g.State = ProfileSelection }
+	return panel
+}
+
+// Sélection de la difficulté, avec les meilleurs scores
+func (g *Game) buildDifficultySelection() *widget.Panel {
+	// le record personnel du joueur est rappelé à côté de chaque difficulté
+	var items []string
+	for _, difficulty := range []Difficulty{Facile, Normal, Difficile} {
+		item := difficulty.name()
+		if best := g.Profile.Best(g.Mode, difficulty.id()); best > 0 {
+			item = i18n.T("difficulty.item_best", item, best)
 		}
+		items = append(items, item)
 	}
-	return nil
+	difficulties := widget.NewList(items, func(index int) {
+		g.Difficulty = Difficulty(index)
+		g.startGame()
+	})
+	difficulties.Selected = int(g.Difficulty)
+
+	panel := widget.NewPanel(widget.NewTitle(i18n.T("difficulty.title")), difficulties)
+	panel.Add(&widget.Label{Text: i18n.T("scores.title"), TextSize: 28})
+	for i, score := range g.Scores {
+		panel.Add(&widget.Label{Text: i18n.T("scores.entry", i+1, score.Name, score.Value), TextSize: 18})
+	}
+	panel.Add(widget.NewButton(i18n.T("common.back"), func() { g.State = ModeSelection }))
+	panel.Spacing = 12
+	panel.OnBack = func() { g.State = ModeSelection }
+	return panel
+}
+
+// Ecran des crédits
+func (g *Game) buildCredits() *widget.Panel {
+	back := func() {
+		g.State = Menu
+	}
+
+	panel := widget.NewPanel(
+		widget.NewTitle(i18n.T("credits.title")),
+		widget.NewLabel(i18n.T("credits.developers", "Florent Weltmann, Dantin Durand")),
+		widget.NewButton(i18n.T("common.back"), back),
+	)
+	panel.OnBack = back
+	return panel
+}
+
+// Boutons de l'écran de fin de partie, placés en bas à gauche du panneau dessiné par ui.RenderGameOver
+// R recommence une partie et Entrée revient au menu, comme l'indiquent les images des touches
+func (g *Game) buildGameOver() *widget.Panel {
+	restart := widget.NewImageButton(ui.Image(resources.RKeyImage), i18n.T("gameover.restart"), func() {
+		g.startGame()
+	}).WithShortcut(ebiten.KeyR)
+	restart.TextSize = 20
+	restart.Color = theme.Current().Palette.PanelText
+
+	menu := widget.NewImageButton(ui.Image(resources.EnterKeyImage), i18n.T("gameover.menu"), func() {
+		g.State = Menu
+	}).WithShortcut(ebiten.KeyEnter, ebiten.KeyNumpadEnter)
+	menu.TextSize = 20
+	menu.Color = theme.Current().Palette.PanelText
+
+	panel := widget.NewPanel(restart, menu)
+	panel.Padding = 0
+	panel.Spacing = 10
+	panel.Position = func(area image.Rectangle, width, height int) image.Point {
+		return ui.GameOverButtonsOrigin(area.Dx(), area.Dy(), height)
+	}
+	return panel
 }
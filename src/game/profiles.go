@@ -0,0 +1,268 @@
+package game
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"snake-go/src/i18n"
+	"snake-go/src/profile"
+	"snake-go/src/theme"
+	"snake-go/src/ui/widget"
+)
+
+// Enregistre les profils, une erreur est seulement signalée dans le journal
+func (g *Game) saveProfiles() {
+	if err := g.Profiles.Save(); err != nil {
+		log.Printf("Impossible d'enregistrer les profils: %v", err)
+	}
+}
+
+// Choisit le profil du joueur pour les prochaines parties
+func (g *Game) selectProfile(p *profile.Profile) {
+	g.Profile = p
+	g.Profiles.Last = p.ID
+	g.saveProfiles()
+	g.State = ModeSelection
+}
+
+// Ouvre l'écran de modification d'un profil
+//
+// p: le profil à modifier, nil pour en créer un nouveau
+func (g *Game) editProfile(p *profile.Profile) {
+	g.editedProfile = p
+	g.State = ProfileEdit
+}
+
+// Message affiché sous le champ du nom pour une erreur de validation
+func profileErrorMessage(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, profile.ErrEmptyName):
+		return i18n.T("profile.error_empty")
+	case errors.Is(err, profile.ErrNameTaken):
+		return i18n.T("profile.error_taken")
+	case errors.Is(err, profile.ErrNameTooLong):
+		return i18n.N("textfield.max_length", profile.MaxNameLength)
+	}
+	return err.Error()
+}
+
+// Choix du profil avant de jouer, chaque profil peut être modifié avec le bouton à sa droite
+func (g *Game) buildProfileSelection() *widget.Panel {
+	panel := widget.NewPanel(widget.NewTitle(i18n.T("profile.select_title")))
+	var last widget.Widget
+	for _, p := range g.Profiles.Profiles {
+		p := p
+		row := widget.NewRow(
+			widget.NewButton(p.Name, func() { g.selectProfile(p) }),
+			&widget.Button{Text: i18n.T("profile.edit"), TextSize: 18, OnClick: func() { g.editProfile(p) }},
+		)
+		panel.Add(row)
+		if p == g.Profiles.LastUsed() {
+			last = row
+		}
+	}
+	panel.Add(
+		widget.NewButton(i18n.T("profile.new"), func() { g.editProfile(nil) }),
+		widget.NewButton(i18n.T("common.back"), func() { g.State = Menu }),
+	)
+	if last != nil {
+		panel.Focus(last)
+	}
+	panel.Spacing = 16
+	panel.OnBack = func() { g.State = Menu }
+	return panel
+}
+
+// Création ou modification d'un profil : nom, couleur et apparence du serpent, touches, puis ses statistiques
+func (g *Game) buildProfileEdit() *widget.Panel {
+	edited := g.editedProfile
+	draft := profile.New("")
+	if edited != nil {
+		copied := *edited
+		draft = &copied
+	}
+	back := func() { g.State = ProfileSelection }
+
+	field := widget.NewTextField(draft.Name, profile.MaxNameLength, nil)
+	field.Accept = acceptNameRune
+	field.Validate = func(text string) string { return profileErrorMessage(g.Profiles.ValidateName(text, edited)) }
+	field.OnChange = func(text string) { draft.Name = text }
+
+	save := func() {
+		var err error
+		if edited == nil {
+			err = g.Profiles.Add(draft)
+		} else if err = g.Profiles.Rename(edited, draft.Name); err == nil {
+			edited.Color, edited.Skin, edited.Controls = draft.Color, draft.Skin, draft.Controls
+		}
+		if err != nil {
+			field.Message = profileErrorMessage(err)
+			return
+		}
+		g.saveProfiles()
+		back()
+	}
+	field.OnSubmit = func(string) { save() }
+
+	colorNames := []string{
+		i18n.T("profile.color_default"), i18n.T("profile.color_red"), i18n.T("profile.color_blue"),
+		i18n.T("profile.color_green"), i18n.T("profile.color_yellow"), i18n.T("profile.color_purple"),
+	}
+	colorSlider := widget.NewSlider(i18n.T("profile.color"), draft.Color, 0, len(profile.Colors)-1, func(index int) {
+		draft.Color = index
+	})
+	colorSlider.Format = func(index int) string { return colorNames[min(index, len(colorNames)-1)] }
+
+	// la première apparence est celle du thème du jeu
+	themes := theme.All()
+	skin := 0
+	for i, t := range themes {
+		if t.ID == draft.Skin {
+			skin = i + 1
+		}
+	}
+	skinSlider := widget.NewSlider(i18n.T("profile.skin"), skin, 0, len(themes), func(index int) {
+		draft.Skin = ""
+		if index > 0 {
+			draft.Skin = themes[index-1].ID
+		}
+	})
+	skinSlider.Format = func(index int) string {
+		if index == 0 {
+			return i18n.T("profile.skin_theme")
+		}
+		return themes[index-1].Name
+	}
+
+	controlNames := map[profile.Controls]string{
+		profile.Arrows:  i18n.T("profile.controls_arrows"),
+		profile.Letters: i18n.T("profile.controls_letters"),
+		profile.Numpad:  i18n.T("profile.controls_numpad"),
+	}
+	controls := 0
+	for i, c := range profile.AllControls {
+		if c == draft.Controls {
+			controls = i
+		}
+	}
+	controlsSlider := widget.NewSlider(i18n.T("profile.controls"), controls, 0, len(profile.AllControls)-1, func(index int) {
+		draft.Controls = profile.AllControls[index]
+	})
+	controlsSlider.Format = func(index int) string { return controlNames[profile.AllControls[index]] }
+
+	title := i18n.T("profile.new_title")
+	if edited != nil {
+		title = i18n.T("profile.edit_title")
+	}
+	panel := widget.NewPanel(widget.NewTitle(title), field, colorSlider, skinSlider, controlsSlider)
+
+	if edited != nil {
+		panel.Add(&widget.Label{Text: i18n.N("profile.games_played", edited.GamesPlayed) + " - " + formatPlayTime(edited.PlayTime), TextSize: 18})
+		for _, mode := range []string{"Classique", "Challenge"} {
+			var bests []string
+			for _, difficulty := range []Difficulty{Facile, Normal, Difficile} {
+				if best := edited.Best(mode, difficulty.id()); best > 0 {
+					bests = append(bests, i18n.T("profile.best", difficulty.name(), best))
+				}
+			}
+			if len(bests) > 0 {
+				panel.Add(&widget.Label{Text: i18n.T("profile.bests", modeName(mode), strings.Join(bests, ", ")), TextSize: 18})
+			}
+		}
+	}
+
+	panel.Add(widget.NewButton(i18n.T("profile.save"), save))
+	if edited != nil {
+		// la suppression demande une confirmation : le bouton change de texte au premier clic
+		var remove *widget.Button
+		remove = widget.NewButton(i18n.T("profile.delete"), func() {
+			if remove.Text != i18n.T("profile.delete_confirm") {
+				remove.Text = i18n.T("profile.delete_confirm")
+				return
+			}
+			g.Profiles.Delete(edited)
+			if g.Profile == edited {
+				g.Profile = nil
+			}
+			g.saveProfiles()
+			back()
+		})
+		panel.Add(remove)
+	}
+	panel.Add(widget.NewButton(i18n.T("common.back"), back))
+	panel.Spacing = 14
+	panel.OnBack = back
+	return panel
+}
+
+// Profil affiché par les écrans des statistiques et des succès : celui choisi sur l'écran,
+// sinon le profil du joueur, sinon le premier profil
+func (g *Game) currentShownProfile() *profile.Profile {
+	for _, p := range []*profile.Profile{g.shownProfile, g.Profile} {
+		if p != nil && g.Profiles.Find(p.ID) != nil {
+			return p
+		}
+	}
+	return g.Profiles.Profiles[0]
+}
+
+// Curseur qui choisit le profil affiché, placé sous le titre des écrans des statistiques et des succès
+//
+// build: construit l'écran, il est reconstruit pour afficher le profil choisi
+func (g *Game) profileSlider(build func() *widget.Panel) *widget.Slider {
+	profiles := g.Profiles.Profiles
+	shown := 0
+	for i, p := range profiles {
+		if p == g.currentShownProfile() {
+			shown = i
+		}
+	}
+	slider := widget.NewSlider(i18n.T("stats.profile"), shown, 0, len(profiles)-1, func(index int) {
+		g.shownProfile = profiles[index]
+		g.menu = build()
+		g.menu.Focus(g.menu.Children[1])
+	})
+	slider.Format = func(index int) string { return profiles[index].Name }
+	return slider
+}
+
+// Ecran affiché à la place des statistiques et des succès tant qu'il n'y a aucun profil
+func (g *Game) buildNoProfile(title string) *widget.Panel {
+	panel := widget.NewPanel(
+		widget.NewTitle(title),
+		widget.NewLabel(i18n.T("stats.no_profile")),
+		widget.NewButton(i18n.T("common.back"), g.leaveProfileScreen),
+	)
+	panel.OnBack = g.leaveProfileScreen
+	return panel
+}
+
+// Retour au menu principal depuis les écrans des statistiques et des succès
+func (g *Game) leaveProfileScreen() {
+	g.shownProfile = nil
+	g.State = Menu
+}
+
+// Caractères acceptés dans le nom d'un profil
+func acceptNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ' || r == '-' || r == '_'
+}
+
+// Nom traduit d'un mode de jeu
+func modeName(mode string) string {
+	if mode == "Challenge" {
+		return i18n.T("mode.challenge")
+	}
+	return i18n.T("mode.classic")
+}
+
+// Temps de jeu total d'un profil, en heures et minutes
+func formatPlayTime(d time.Duration) string {
+	minutes := int(d.Minutes())
+	return i18n.T("profile.play_time", minutes/60, minutes%60)
+}
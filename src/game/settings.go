@@ -0,0 +1,219 @@
+package game
+
+import (
+	"log"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"snake-go/src/audio"
+	"snake-go/src/config"
+	"snake-go/src/constants"
+	"snake-go/src/i18n"
+	"snake-go/src/theme"
+	"snake-go/src/ui/widget"
+)
+
+// Ecran des paramètres, la configuration est enregistrée en revenant au menu principal
+func (g *Game) buildSettings() *widget.Panel {
+	themes := theme.All()
+	current := 0
+	for i, t := range themes {
+		if t.ID == theme.Current().ID {
+			current = i
+		}
+	}
+	themeSlider := widget.NewSlider(i18n.T("settings.theme"), current, 0, len(themes)-1, func(index int) {
+		theme.Select(themes[index].ID)
+		audio.ApplyTheme(themes[index].ReadFile, audio.SoundFiles(themes[index].Sounds))
+		g.Config.Theme = themes[index].ID
+	})
+	themeSlider.Format = func(index int) string { return themes[index].Name }
+
+	languages := i18n.Languages()
+	currentLanguage := 0
+	for i, l := range languages {
+		if l.Code == i18n.CurrentLanguage() {
+			currentLanguage = i
+		}
+	}
+	languageSlider := widget.NewSlider(i18n.T("settings.language"), currentLanguage, 0, len(languages)-1, func(index int) {
+		i18n.SetLanguage(languages[index].Code)
+		g.Config.Language = languages[index].Code
+		// l'écran est reconstruit pour afficher ses libellés dans la nouvelle langue
+		g.menu = g.buildSettings()
+		g.menu.Focus(g.menu.Children[1])
+	})
+	languageSlider.Format = func(index int) string { return languages[index].Name }
+
+	back := func() {
+		if err := g.Config.Save(); err != nil {
+			log.Printf("Impossible d'enregistrer la configuration: %v", err)
+		}
+		g.State = Menu
+	}
+
+	panel := widget.NewPanel(
+		widget.NewTitle(i18n.T("settings.title")),
+		languageSlider,
+		themeSlider,
+		widget.NewSlider(i18n.T("settings.board_width"), g.Config.BoardWidth, constants.MinBoardSize, constants.MaxBoardSize, func(value int) {
+			g.Config.BoardWidth = value
+		}),
+		widget.NewSlider(i18n.T("settings.board_height"), g.Config.BoardHeight, constants.MinBoardSize, constants.MaxBoardSize, func(value int) {
+			g.Config.BoardHeight = value
+		}),
+		widget.NewToggle(i18n.T("settings.fullscreen"), g.Config.Fullscreen, func(value bool) {
+			g.Config.Fullscreen = value
+			ebiten.SetFullscreen(value)
+		}),
+		widget.NewButton(i18n.T("settings.audio"), func() { g.State = AudioSettings }),
+		widget.NewButton(i18n.T("settings.visual_effects"), func() { g.State = EffectsSettings }),
+		widget.NewButton(i18n.T("settings.accessibility"), func() { g.State = AccessSettings }),
+		widget.NewButton(i18n.T("settings.captures"), func() { g.State = CaptureSettings }),
+		widget.NewButton(i18n.T("common.back"), back),
+	)
+	panel.OnBack = back
+	return panel
+}
+
+// Ecran des réglages du son, ouvert depuis les paramètres qui enregistrent la configuration en revenant au menu
+func (g *Game) buildAudioSettings() *widget.Panel {
+	back := func() { g.State = Settings }
+	panel := widget.NewPanel(
+		widget.NewTitle(i18n.T("settings.audio")),
+		g.volumeSlider(i18n.T("settings.master_volume"), &g.Config.MasterVolume),
+		g.volumeSlider(i18n.T("settings.music_volume"), &g.Config.MusicVolume),
+		g.volumeSlider(i18n.T("settings.effects_volume"), &g.Config.EffectsVolume),
+		widget.NewToggle(i18n.T("settings.mute"), g.Config.Muted, func(value bool) {
+			g.Config.Muted = value
+			ApplyAudioSettings(g.Config)
+		}),
+		widget.NewToggle(i18n.T("settings.shuffle_music"), g.Config.ShuffleMusic, func(value bool) {
+			g.Config.ShuffleMusic = value
+			ApplyAudioSettings(g.Config)
+		}),
+		widget.NewToggle(i18n.T("settings.synth_sounds"), g.Config.SynthSounds, func(value bool) {
+			g.Config.SynthSounds = value
+			ApplyAudioSettings(g.Config)
+		}),
+		widget.NewButton(i18n.T("common.back"), back),
+	)
+	panel.OnBack = back
+	return panel
+}
+
+// Ecran des effets visuels, ouvert depuis les paramètres, les réglages s'appliquent à la prochaine partie
+func (g *Game) buildEffectsSettings() *widget.Panel {
+	back := func() { g.State = Settings }
+	effects := &g.Config.Effects
+	panel := widget.NewPanel(
+		widget.NewTitle(i18n.T("settings.visual_effects")),
+		widget.NewToggle(i18n.T("settings.particles"), effects.Particles, func(value bool) { effects.Particles = value }),
+		widget.NewToggle(i18n.T("settings.shake"), effects.Shake, func(value bool) { effects.Shake = value }),
+		widget.NewToggle(i18n.T("settings.flash"), effects.Flash, func(value bool) { effects.Flash = value }),
+		widget.NewToggle(i18n.T("settings.score_pulse"), effects.ScorePulse, func(value bool) { effects.ScorePulse = value }),
+		widget.NewToggle(i18n.T("settings.slow_motion"), effects.SlowMotion, func(value bool) { effects.SlowMotion = value }),
+		widget.NewButton(i18n.T("settings.post_fx"), func() { g.State = PostFXSettings }),
+		widget.NewButton(i18n.T("common.back"), back),
+	)
+	panel.OnBack = back
+	return panel
+}
+
+// Ecran des shaders appliqués à tout l'écran, visibles dès qu'ils sont activés
+func (g *Game) buildPostFXSettings() *widget.Panel {
+	back := func() { g.State = EffectsSettings }
+	postFX := &g.Config.PostFX
+	panel := widget.NewPanel(
+		widget.NewTitle(i18n.T("settings.post_fx")),
+		widget.NewToggle(i18n.T("settings.crt"), postFX.CRT, func(value bool) { postFX.CRT = value }),
+		widget.NewToggle(i18n.T("settings.bloom"), postFX.Bloom, func(value bool) { postFX.Bloom = value }),
+		widget.NewToggle(i18n.T("settings.vignette"), postFX.Vignette, func(value bool) { postFX.Vignette = value }),
+		widget.NewToggle(i18n.T("settings.grayscale"), postFX.Grayscale, func(value bool) { postFX.Grayscale = value }),
+		widget.NewButton(i18n.T("common.back"), back),
+	)
+	panel.OnBack = back
+	return panel
+}
+
+// Ecran des options d'accessibilité, les couleurs et la taille de l'interface changent dès qu'elles sont réglées
+func (g *Game) buildAccessSettings() *widget.Panel {
+	back := func() { g.State = Settings }
+	access := &g.Config.Access
+
+	vision := slices.Index(config.ColorVisions, access.ColorVision)
+	visionSlider := widget.NewSlider(i18n.T("settings.color_vision"), max(vision, 0), 0, len(config.ColorVisions)-1, func(index int) {
+		access.ColorVision = config.ColorVisions[index]
+	})
+	visionNames := map[string]string{
+		config.NormalVision: i18n.T("settings.color_vision_normal"),
+		config.Protanopia:   i18n.T("settings.color_vision_protanopia"),
+		config.Deuteranopia: i18n.T("settings.color_vision_deuteranopia"),
+		config.Tritanopia:   i18n.T("settings.color_vision_tritanopia"),
+	}
+	visionSlider.Format = func(index int) string { return visionNames[config.ColorVisions[index]] }
+
+	scaleSlider := widget.NewSlider(i18n.T("settings.ui_scale"), access.UIScale, config.MinUIScale, config.MaxUIScale, func(value int) {
+		access.UIScale = value
+	})
+	scaleSlider.Step = 25
+	scaleSlider.Format = func(value int) string { return i18n.T("settings.ui_scale_value", value) }
+
+	panel := widget.NewPanel(
+		widget.NewTitle(i18n.T("settings.accessibility")),
+		visionSlider,
+		widget.NewToggle(i18n.T("settings.high_contrast"), access.HighContrast, func(value bool) { access.HighContrast = value }),
+		widget.NewToggle(i18n.T("settings.shape_cues"), access.ShapeCues, func(value bool) { access.ShapeCues = value }),
+		scaleSlider,
+		widget.NewToggle(i18n.T("settings.reduced_motion"), access.ReducedMotion, func(value bool) { access.ReducedMotion = value }),
+		widget.NewToggle(i18n.T("settings.assist_speed"), access.AssistSpeed, func(value bool) { access.AssistSpeed = value }),
+		widget.NewToggle(i18n.T("settings.narration"), access.Narration, func(value bool) { access.Narration = value }),
+		widget.NewToggle(i18n.T("settings.audio_cues"), access.AudioCues, func(value bool) { access.AudioCues = value }),
+		widget.NewButton(i18n.T("common.back"), back),
+	)
+	panel.OnBack = back
+	return panel
+}
+
+// Ecran des réglages des clips, enregistrés avec F9 pendant la partie
+func (g *Game) buildCaptureSettings() *widget.Panel {
+	back := func() { g.State = Settings }
+	capture := &g.Config.Capture
+
+	seconds := widget.NewSlider(i18n.T("settings.clip_length"), capture.ClipSeconds, config.MinClipSeconds, config.MaxClipSeconds, func(value int) {
+		capture.ClipSeconds = value
+	})
+	seconds.Step = 5
+	seconds.Format = func(value int) string { return i18n.T("settings.clip_length_value", value) }
+
+	panel := widget.NewPanel(
+		widget.NewTitle(i18n.T("settings.captures")),
+		seconds,
+		widget.NewToggle(i18n.T("settings.clip_apng"), capture.APNG, func(value bool) { capture.APNG = value }),
+		widget.NewButton(i18n.T("common.back"), back),
+	)
+	panel.OnBack = back
+	return panel
+}
+
+// curseur d'un volume de la configuration, de 10 en 10 %, appliqué dès qu'il change
+func (g *Game) volumeSlider(label string, volume *int) *widget.Slider {
+	slider := widget.NewSlider(label, *volume, 0, 100, func(value int) {
+		*volume = value
+		ApplyAudioSettings(g.Config)
+	})
+	slider.Step = 10
+	slider.Format = func(value int) string { return i18n.T("settings.volume_value", value) }
+	return slider
+}
+
+// ApplyAudioSettings règle les volumes, la coupure du son, la lecture aléatoire et les bruitages selon la configuration
+func ApplyAudioSettings(cfg config.Config) {
+	audio.SetVolume(audio.Master, float64(cfg.MasterVolume)/100)
+	audio.SetVolume(audio.Music, float64(cfg.MusicVolume)/100)
+	audio.SetVolume(audio.Effects, float64(cfg.EffectsVolume)/100)
+	audio.SetMuted(cfg.Muted)
+	audio.SetShuffle(cfg.ShuffleMusic)
+	audio.SetSynthesized(cfg.SynthSounds)
+}
@@ -0,0 +1,118 @@
+package game
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"snake-go/src/board"
+	"snake-go/src/config"
+	"snake-go/src/i18n"
+	"snake-go/src/profile"
+	"snake-go/src/ui/widget"
+)
+
+// Ecran des statistiques d'un profil, avec leur export en CSV et en JSON
+func (g *Game) buildStatistics() *widget.Panel {
+	back := g.leaveProfileScreen
+	if len(g.Profiles.Profiles) == 0 {
+		return g.buildNoProfile(i18n.T("stats.title"))
+	}
+	p := g.currentShownProfile()
+	stats := p.Stats
+	line := func(text string) *widget.Label { return &widget.Label{Text: text, TextSize: 18} }
+
+	labels := make([]string, profile.HistogramBuckets)
+	for i := range labels {
+		labels[i] = profile.HistogramLabel(i)
+	}
+	scores := make([]int, profile.HistogramBuckets)
+	copy(scores, stats.Scores)
+
+	message := line("")
+	export := widget.NewButton(i18n.T("stats.export"), func() {
+		if dir, err := exportStatistics(p); err != nil {
+			message.Text = i18n.T("stats.export_error", err)
+		} else {
+			message.Text = i18n.T("stats.exported", dir)
+		}
+	})
+
+	panel := widget.NewPanel(
+		widget.NewTitle(i18n.T("stats.title")),
+		g.profileSlider(g.buildStatistics),
+		line(i18n.N("profile.games_played", p.GamesPlayed)+" - "+formatPlayTime(p.PlayTime)),
+		line(i18n.T("stats.average_game", formatGameLength(p.AverageGame()))),
+		line(i18n.T("stats.apples", stats.ApplesEaten)),
+		line(i18n.T("stats.longest", stats.LongestSnake)),
+		line(i18n.N("stats.distance", stats.Distance)),
+		line(i18n.T("stats.deaths", deathSummary(stats.Deaths))),
+		line(i18n.T("stats.scores")),
+		widget.NewHistogram(scores, labels),
+		export,
+		message,
+		widget.NewButton(i18n.T("common.back"), back),
+	)
+	panel.Spacing = 10
+	panel.OnBack = back
+	return panel
+}
+
+// Morts par cause, dans l'ordre des causes, par exemple "Collision avec un mur 3, Collision avec soi-meme 1"
+func deathSummary(deaths map[string]int) string {
+	var parts []string
+	for cause := board.WallCollision; cause <= board.TimeoutDeath; cause++ {
+		if n := deaths[cause.ID()]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", cause, n))
+		}
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Durée moyenne d'une partie, en minutes et secondes
+func formatGameLength(d time.Duration) string {
+	seconds := int(d.Seconds())
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// Exporte les statistiques d'un profil dans deux fichiers, CSV et JSON, du dossier des exports
+// Retourne le dossier des fichiers créés
+func exportStatistics(p *profile.Profile) (string, error) {
+	dir := config.ExportsDir()
+	if dir == "" {
+		return "", fmt.Errorf("dossier de configuration introuvable")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	name := strings.Map(func(r rune) rune {
+		if r == ' ' || r == os.PathSeparator {
+			return '_'
+		}
+		return r
+	}, p.Name)
+	base := filepath.Join(dir, fmt.Sprintf("stats-%s-%s", name, time.Now().Format("20060102-150405")))
+	for ext, write := range map[string]func(*os.File) error{
+		".csv":  func(f *os.File) error { return p.ExportCSV(f) },
+		".json": func(f *os.File) error { return p.ExportJSON(f) },
+	} {
+		f, err := os.Create(base + ext)
+		if err != nil {
+			return "", err
+		}
+		err = write(f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
@@ -0,0 +1,233 @@
+// Package i18n traduit les textes de l'interface à partir de catalogues de messages, un fichier JSON par langue
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultLanguage est la langue utilisée quand un message manque dans la langue choisie
+const DefaultLanguage = "fr"
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+// message est un texte simple ou un texte avec ses formes plurielles ("one", "other")
+type message struct {
+	text   string
+	plural map[string]string
+}
+
+func (m *message) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &m.text); err == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, &m.plural); err != nil {
+		return fmt.Errorf("un message doit être un texte ou un objet de formes plurielles: %w", err)
+	}
+	return nil
+}
+
+// catalog contient les messages d'une langue
+type catalog struct {
+	code     string
+	name     string
+	rule     string
+	messages map[string]message
+}
+
+// Language décrit une langue disponible
+type Language struct {
+	Code string
+	Name string
+}
+
+// règles de pluriel : retourne la forme à utiliser pour n
+var pluralRules = map[string]func(n int) string{
+	// en français, 0 et 1 sont au singulier
+	"fr": func(n int) string {
+		if n == 0 || n == 1 || n == -1 {
+			return "one"
+		}
+		return "other"
+	},
+	"en": func(n int) string {
+		if n == 1 || n == -1 {
+			return "one"
+		}
+		return "other"
+	},
+}
+
+// Variables globales
+var (
+	catalogs = map[string]*catalog{}
+	current  *catalog
+)
+
+func init() {
+	entries, err := localesFS.ReadDir("locales")
+	if err != nil {
+		log.Printf("Attention: catalogues de langue introuvables: %v", err)
+		return
+	}
+	for _, entry := range entries {
+		data, err := localesFS.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			log.Printf("Attention: catalogue %s illisible: %v", entry.Name(), err)
+			continue
+		}
+		if err := register(strings.TrimSuffix(entry.Name(), ".json"), data); err != nil {
+			log.Printf("Attention: catalogue %s invalide: %v", entry.Name(), err)
+		}
+	}
+	current = catalogs[DefaultLanguage]
+}
+
+// LoadDir ajoute les catalogues d'un dossier, un fichier <code>.json par langue
+// Un catalogue portant le code d'une langue existante la remplace, les messages absents sont pris dans la langue par défaut
+//
+// dir: le dossier des catalogues, ignoré s'il n'existe pas
+func LoadDir(dir string) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err == nil {
+			err = register(strings.TrimSuffix(filepath.Base(file), ".json"), data)
+		}
+		if err != nil {
+			log.Printf("Attention: catalogue %s ignoré: %v", file, err)
+		}
+	}
+}
+
+// analyse un catalogue et l'enregistre sous le code de langue donné
+func register(code string, data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	c := &catalog{code: code, name: code, rule: code, messages: map[string]message{}}
+	for key, value := range raw {
+		switch key {
+		case "_name":
+			if err := json.Unmarshal(value, &c.name); err != nil {
+				return fmt.Errorf("_name: %w", err)
+			}
+		case "_plural":
+			if err := json.Unmarshal(value, &c.rule); err != nil {
+				return fmt.Errorf("_plural: %w", err)
+			}
+		default:
+			var m message
+			if err := json.Unmarshal(value, &m); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			c.messages[key] = m
+		}
+	}
+
+	catalogs[code] = c
+	return nil
+}
+
+// SetLanguage change la langue de l'interface
+//
+// code: le code de la langue (fr, en...)
+// Retourne faux si la langue n'existe pas, la langue courante est alors conservée
+func SetLanguage(code string) bool {
+	c, ok := catalogs[code]
+	if ok {
+		current = c
+	}
+	return ok
+}
+
+// CurrentLanguage retourne le code de la langue de l'interface
+func CurrentLanguage() string {
+	if current == nil {
+		return DefaultLanguage
+	}
+	return current.code
+}
+
+// Languages retourne les langues disponibles, triées par code
+func Languages() []Language {
+	languages := make([]Language, 0, len(catalogs))
+	for _, c := range catalogs {
+		languages = append(languages, Language{Code: c.code, Name: c.name})
+	}
+	sort.Slice(languages, func(i, j int) bool { return languages[i].Code < languages[j].Code })
+	return languages
+}
+
+// cherche un message dans la langue courante puis dans la langue par défaut
+func lookup(key string) (message, *catalog, bool) {
+	for _, c := range []*catalog{current, catalogs[DefaultLanguage]} {
+		if c == nil {
+			continue
+		}
+		if m, ok := c.messages[key]; ok {
+			return m, c, true
+		}
+	}
+	return message{}, nil, false
+}
+
+// T retourne le message traduit, formaté avec args comme fmt.Sprintf
+// Un message introuvable est remplacé par sa clé pour rester visible à l'écran
+//
+// key: la clé du message
+// args: les valeurs à insérer dans le message
+func T(key string, args ...any) string {
+	m, _, ok := lookup(key)
+	if !ok {
+		return key
+	}
+	text := m.text
+	if m.plural != nil {
+		text = m.plural["other"]
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// N retourne la forme du message qui correspond à la quantité n, formatée avec n puis args
+//
+// key: la clé du message
+// n: la quantité qui détermine la forme plurielle
+// args: les valeurs à insérer après n
+func N(key string, n int, args ...any) string {
+	m, c, ok := lookup(key)
+	if !ok {
+		return key
+	}
+
+	text := m.text
+	if m.plural != nil {
+		rule, ok := pluralRules[c.rule]
+		if !ok {
+			rule = pluralRules["en"]
+		}
+		text, ok = m.plural[rule(n)]
+		if !ok {
+			text = m.plural["other"]
+		}
+	}
+	if !strings.Contains(text, "%") {
+		return text
+	}
+	return fmt.Sprintf(text, append([]any{n}, args...)...)
+}
@@ -0,0 +1,99 @@
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// appels de traduction dont la clé est un texte littéral, les clés construites ("achievement." + id) sont vérifiées par leur paquet
+var keyPattern = regexp.MustCompile(`i18n\.([TN])\("([^"]+)"\s*[,)]`)
+
+// clés utilisées par le code du jeu, avec la fonction qui les traduit (T ou N)
+func usedKeys(t *testing.T) map[string]string {
+	t.Helper()
+	keys := map[string]string{}
+	err := filepath.WalkDir(filepath.Join("..", ".."), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, match := range keyPattern.FindAllStringSubmatch(string(data), -1) {
+			keys[match[2]] = match[1]
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) == 0 {
+		t.Fatal("aucune clé de traduction trouvée dans le code")
+	}
+	return keys
+}
+
+func TestCatalogsContainUsedKeys(t *testing.T) {
+	for key, function := range usedKeys(t) {
+		for code, c := range catalogs {
+			m, ok := c.messages[key]
+			switch {
+			case !ok:
+				t.Errorf("%s: clé %q manquante", code, key)
+			case function == "N" && m.plural == nil:
+				t.Errorf("%s: la clé %q est utilisée avec N mais n'a pas de formes plurielles", code, key)
+			case function == "T" && m.plural != nil:
+				t.Errorf("%s: la clé %q est utilisée avec T mais a des formes plurielles", code, key)
+			}
+		}
+	}
+}
+
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	reference := catalogs[DefaultLanguage]
+	if reference == nil {
+		t.Fatalf("catalogue par défaut %q absent", DefaultLanguage)
+	}
+	for code, c := range catalogs {
+		for key := range reference.messages {
+			if _, ok := c.messages[key]; !ok {
+				t.Errorf("%s: clé %q manquante", code, key)
+			}
+		}
+		for key, m := range c.messages {
+			if _, ok := reference.messages[key]; !ok {
+				t.Errorf("%s: clé %q absente du catalogue par défaut", code, key)
+			}
+			if m.plural != nil && m.plural["other"] == "" {
+				t.Errorf("%s: la clé %q n'a pas de forme \"other\"", code, key)
+			}
+		}
+	}
+}
+
+func TestPlural(t *testing.T) {
+	defer SetLanguage(CurrentLanguage())
+
+	SetLanguage("fr")
+	if got := N("hud.lives", 1); got != N("hud.lives", 0) {
+		t.Errorf("fr: 0 et 1 devraient utiliser la même forme, obtenu %q et %q", N("hud.lives", 0), got)
+	}
+	SetLanguage("en")
+	if N("hud.lives", 0) == N("hud.lives", 1) {
+		t.Errorf("en: 0 devrait utiliser le pluriel, obtenu %q", N("hud.lives", 0))
+	}
+}
+
+func TestMissingKey(t *testing.T) {
+	if got := T("cle.inexistante"); got != "cle.inexistante" {
+		t.Errorf("T devrait retourner la clé d'un message absent, obtenu %q", got)
+	}
+}
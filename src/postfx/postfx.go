@@ -0,0 +1,181 @@
+// Package postfx applique des shaders Kage à l'écran entier une fois l'image dessinée :
+// halo autour de la pomme, couleurs adaptées aux daltonismes, passage en niveaux de gris à la fin de partie,
+// vignette et écran cathodique
+//
+// Les passes activées sont enchaînées dans cet ordre, chacune lit l'image produite par la précédente.
+package postfx
+
+import (
+	"embed"
+	"image/color"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"snake-go/src/config"
+)
+
+//go:embed shaders/*.kage
+var shadersFS embed.FS
+
+// Réglages des passes
+const (
+	grayscaleTicks   = 45   // durée du passage en niveaux de gris à la fin de partie
+	bloomStrength    = 0.6  // intensité du halo autour de la pomme
+	bloomRadius      = 1.5  // rayon du halo, en cellules
+	vignetteStrength = 0.6  // assombrissement des coins
+	crtCurvature     = 0.04 // courbure de l'écran cathodique
+	crtScanlines     = 0.25 // assombrissement d'une ligne sur deux
+)
+
+// matrices des couleurs pour chaque daltonisme, une ligne par composante du résultat
+// Le rouge et le vert, confondus par les protanopes et les deutéranopes, deviennent orange et bleu ;
+// le bleu et le jaune, confondus par les tritanopes, s'écartent vers le violet et le vert
+// Chaque ligne a une somme de 1, les gris ne changent pas
+var colorVisions = map[string][3][3]float32{
+	config.Protanopia:   {{1.3, -0.3, 0}, {0.5, 0.5, 0}, {-0.5, 0.9, 0.6}},
+	config.Deuteranopia: {{1.2, -0.2, 0}, {0.55, 0.45, 0}, {-0.4, 0.8, 0.6}},
+	config.Tritanopia:   {{0.8, -0.2, 0.4}, {0, 1, 0}, {-0.2, 0.4, 0.8}},
+}
+
+// pass est un shader appliqué à l'écran, avec ses paramètres pour l'image en cours
+type pass struct {
+	name     string
+	uniforms map[string]any
+}
+
+// Pipeline enchaîne les shaders activés sur l'image finale du jeu
+type Pipeline struct {
+	Options     config.PostFX // passes activées, suivies à chaque image
+	ColorVision string        // daltonisme auquel les couleurs sont adaptées, config.NormalVision sinon
+	gray        float64       // part de gris, monte jusqu'à 1 pendant la fin de partie
+	glowX       float64       // centre du halo de l'image en cours, en pixels
+	glowY       float64
+	glowSize    float64 // côté de la cellule entourée, 0 sans halo
+	glow        color.Color
+	buffers     [2]*ebiten.Image
+	shaders     map[string]*ebiten.Shader // shaders compilés, nil pour un shader qui n'a pas compilé
+}
+
+// Update fait avancer le passage en niveaux de gris, à appeler à chaque tick
+//
+// gameOver: vrai pendant l'écran de fin de partie
+func (p *Pipeline) Update(gameOver bool) {
+	if gameOver && p.Options.Grayscale {
+		p.gray = min(p.gray+1.0/grayscaleTicks, 1)
+	} else {
+		p.gray = 0
+	}
+}
+
+// Highlight entoure une cellule d'un halo pour l'image en cours, si le halo est activé
+//
+// x, y: le coin supérieur gauche de la cellule à l'écran
+// cellSize: le côté de la cellule en pixels
+// c: la couleur du halo
+func (p *Pipeline) Highlight(x, y, cellSize float64, c color.Color) {
+	p.glowX, p.glowY, p.glowSize, p.glow = x+cellSize/2, y+cellSize/2, cellSize, c
+}
+
+// Begin retourne l'image sur laquelle dessiner le jeu : l'écran lui-même si aucune passe n'est active,
+// sinon une image hors écran que End traite avant de la copier à l'écran
+func (p *Pipeline) Begin(screen *ebiten.Image) *ebiten.Image {
+	p.glowSize = 0
+	if !p.active() {
+		return screen
+	}
+	bounds := screen.Bounds()
+	for i, buffer := range p.buffers {
+		if buffer == nil || buffer.Bounds().Size() != bounds.Size() {
+			p.buffers[i] = ebiten.NewImage(bounds.Dx(), bounds.Dy())
+		}
+	}
+	p.buffers[0].Clear()
+	return p.buffers[0]
+}
+
+// End applique les passes actives à l'image dessinée depuis Begin et dessine le résultat à l'écran
+func (p *Pipeline) End(screen *ebiten.Image) {
+	if !p.active() || p.buffers[0] == nil {
+		return
+	}
+	passes := p.passes()
+	if len(passes) == 0 { // aucune passe à appliquer sur cette image, seulement la copie
+		screen.DrawImage(p.buffers[0], nil)
+		return
+	}
+
+	src := p.buffers[0]
+	for i, ps := range passes {
+		dst := screen
+		if i < len(passes)-1 {
+			dst = p.buffers[(i+1)%2]
+			dst.Clear()
+		}
+		bounds := src.Bounds()
+		opts := &ebiten.DrawRectShaderOptions{Uniforms: ps.uniforms}
+		opts.Images[0] = src
+		dst.DrawRectShader(bounds.Dx(), bounds.Dy(), p.shader(ps.name), opts)
+		src = dst
+	}
+}
+
+// vrai si une passe peut être appliquée, l'image doit alors être dessinée hors écran
+func (p *Pipeline) active() bool {
+	o := p.Options
+	return o.Bloom || p.ColorVision != config.NormalVision || p.gray > 0 || o.Vignette || o.CRT
+}
+
+// passes à appliquer sur l'image en cours, dans l'ordre, sans celles dont le shader n'a pas compilé
+func (p *Pipeline) passes() []pass {
+	var passes []pass
+	if p.Options.Bloom && p.glowSize > 0 {
+		r, g, b, _ := p.glow.RGBA()
+		passes = append(passes, pass{"bloom", map[string]any{
+			"Center":   []float32{float32(p.glowX), float32(p.glowY)},
+			"Radius":   float32(p.glowSize * bloomRadius),
+			"Strength": float32(bloomStrength),
+			"Glow":     []float32{float32(r) / 0xffff, float32(g) / 0xffff, float32(b) / 0xffff},
+		}})
+	}
+	if m, ok := colorVisions[p.ColorVision]; ok {
+		passes = append(passes, pass{"colorvision", map[string]any{"Red": m[0][:], "Green": m[1][:], "Blue": m[2][:]}})
+	}
+	if p.gray > 0 {
+		passes = append(passes, pass{"grayscale", map[string]any{"Amount": float32(p.gray)}})
+	}
+	if p.Options.Vignette {
+		passes = append(passes, pass{"vignette", map[string]any{"Strength": float32(vignetteStrength)}})
+	}
+	if p.Options.CRT {
+		passes = append(passes, pass{"crt", map[string]any{"Curvature": float32(crtCurvature), "Scanlines": float32(crtScanlines)}})
+	}
+
+	compiled := passes[:0]
+	for _, ps := range passes {
+		if p.shader(ps.name) != nil {
+			compiled = append(compiled, ps)
+		}
+	}
+	return compiled
+}
+
+// shader compilé au premier usage, nil s'il est invalide
+func (p *Pipeline) shader(name string) *ebiten.Shader {
+	if s, ok := p.shaders[name]; ok {
+		return s
+	}
+	if p.shaders == nil {
+		p.shaders = map[string]*ebiten.Shader{}
+	}
+	var s *ebiten.Shader
+	src, err := shadersFS.ReadFile("shaders/" + name + ".kage")
+	if err == nil {
+		s, err = ebiten.NewShader(src)
+	}
+	if err != nil {
+		log.Printf("Attention: shader %s indisponible: %v", name, err)
+	}
+	p.shaders[name] = s
+	return s
+}
@@ -0,0 +1,205 @@
+// Package profile gère les profils des joueurs : nom, apparence du serpent, touches préférées,
+// meilleurs scores par mode et difficulté, temps de jeu et nombre de parties
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxNameLength est le nombre maximal de caractères du nom d'un profil
+const MaxNameLength = 16
+
+// Erreurs de validation du nom d'un profil
+var (
+	ErrEmptyName   = errors.New("le nom du profil est vide")
+	ErrNameTooLong = errors.New("le nom du profil est trop long")
+	ErrNameTaken   = errors.New("un profil porte déjà ce nom")
+)
+
+// Controls désigne les touches utilisées pour diriger le serpent
+type Controls string
+
+const (
+	Arrows  Controls = "arrows"  // flèches
+	Letters Controls = "letters" // ZQSD sur un clavier AZERTY, WASD sur un clavier QWERTY
+	Numpad  Controls = "numpad"  // 8, 4, 5 et 6 du pavé numérique
+)
+
+// AllControls liste les jeux de touches proposés, dans l'ordre des paramètres du profil
+var AllControls = []Controls{Arrows, Letters, Numpad}
+
+// Colors sont les couleurs proposées pour le serpent d'un profil, la première ne modifie pas les sprites
+var Colors = []color.RGBA{
+	{255, 255, 255, 255},
+	{255, 120, 120, 255},
+	{120, 200, 255, 255},
+	{140, 255, 140, 255},
+	{255, 220, 100, 255},
+	{220, 140, 255, 255},
+}
+
+// Profile contient les informations et les records d'un joueur
+type Profile struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Color       int            `json:"color"`          // indice dans Colors
+	Skin        string         `json:"skin,omitempty"` // thème dont les sprites dessinent le serpent, le thème courant si vide
+	Controls    Controls       `json:"controls"`
+	Bests       map[string]int `json:"bests,omitempty"` // meilleur score par mode et difficulté, voir BestKey
+	PlayTime    time.Duration  `json:"play_time"`
+	GamesPlayed int            `json:"games_played"`
+	Stats       Stats          `json:"stats"`
+	// Achievements associe aux succès débloqués leur date de déblocage
+	Achievements map[string]time.Time `json:"achievements,omitempty"`
+}
+
+// New crée un profil avec les réglages par défaut
+func New(name string) *Profile {
+	return &Profile{Name: name, Controls: Arrows}
+}
+
+// Tint retourne la couleur du serpent du profil
+func (p *Profile) Tint() color.RGBA {
+	if p.Color < 0 || p.Color >= len(Colors) {
+		return Colors[0]
+	}
+	return Colors[p.Color]
+}
+
+// BestKey retourne la clé des meilleurs scores pour un mode et une difficulté
+func BestKey(mode, difficulty string) string {
+	return mode + "/" + difficulty
+}
+
+// Best retourne le meilleur score du profil dans un mode et une difficulté, 0 s'il n'y a pas encore joué
+func (p *Profile) Best(mode, difficulty string) int {
+	return p.Bests[BestKey(mode, difficulty)]
+}
+
+// Store est la liste des profils enregistrés
+type Store struct {
+	Profiles []*Profile `json:"profiles"`
+	Last     string     `json:"last,omitempty"` // identifiant du dernier profil utilisé
+	path     string
+}
+
+// Load charge les profils depuis le fichier donné
+// Retourne une liste vide si le fichier n'existe pas ou ne peut pas être lu
+func Load(path string) *Store {
+	s := &Store{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Impossible de lire les profils: %v", err)
+		}
+		return s
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		log.Printf("Profils invalides, ils sont ignorés: %v", err)
+		return &Store{path: path}
+	}
+	return s
+}
+
+// Save enregistre les profils dans le fichier d'où ils ont été chargés
+func (s *Store) Save() error {
+	if s.path == "" {
+		return errors.New("aucun fichier de profils")
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+// Find retourne le profil qui a l'identifiant donné, ou nil
+func (s *Store) Find(id string) *Profile {
+	for _, p := range s.Profiles {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// LastUsed retourne le dernier profil utilisé, ou nil
+func (s *Store) LastUsed() *Profile {
+	return s.Find(s.Last)
+}
+
+// ValidateName vérifie le nom d'un profil : non vide, pas trop long et différent de celui des autres profils
+//
+// name: le nom à vérifier, les espaces autour sont ignorés
+// p: le profil renommé, nil pour un nouveau profil
+func (s *Store) ValidateName(name string, p *Profile) error {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "":
+		return ErrEmptyName
+	case utf8.RuneCountInString(name) > MaxNameLength:
+		return ErrNameTooLong
+	}
+	for _, other := range s.Profiles {
+		if other != p && strings.EqualFold(other.Name, name) {
+			return ErrNameTaken
+		}
+	}
+	return nil
+}
+
+// Add ajoute un nouveau profil et lui attribue un identifiant
+func (s *Store) Add(p *Profile) error {
+	if err := s.ValidateName(p.Name, nil); err != nil {
+		return err
+	}
+	p.Name = strings.TrimSpace(p.Name)
+	p.ID = s.newID()
+	s.Profiles = append(s.Profiles, p)
+	return nil
+}
+
+// Rename change le nom d'un profil
+func (s *Store) Rename(p *Profile, name string) error {
+	if err := s.ValidateName(name, p); err != nil {
+		return err
+	}
+	p.Name = strings.TrimSpace(name)
+	return nil
+}
+
+// Delete supprime un profil
+func (s *Store) Delete(p *Profile) {
+	for i, other := range s.Profiles {
+		if other == p {
+			s.Profiles = append(s.Profiles[:i], s.Profiles[i+1:]...)
+			break
+		}
+	}
+	if s.Last == p.ID {
+		s.Last = ""
+	}
+}
+
+// identifiant unique, dérivé de l'heure de création
+func (s *Store) newID() string {
+	for n := time.Now().UnixNano(); ; n++ {
+		id := fmt.Sprintf("%x", n)
+		if s.Find(id) == nil {
+			return id
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package profile
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreNames(t *testing.T) {
+	s := &Store{}
+	alice := New("Alice")
+	if err := s.Add(alice); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(New(" alice ")); !errors.Is(err, ErrNameTaken) {
+		t.Errorf("nom déjà pris: obtenu %v", err)
+	}
+	if err := s.Add(New("   ")); !errors.Is(err, ErrEmptyName) {
+		t.Errorf("nom vide: obtenu %v", err)
+	}
+	if err := s.Add(New("Élodie-Françoise-Marie")); !errors.Is(err, ErrNameTooLong) {
+		t.Errorf("nom trop long: obtenu %v", err)
+	}
+	if err := s.Rename(alice, "ALICE"); err != nil {
+		t.Errorf("un profil peut changer la casse de son propre nom: %v", err)
+	}
+
+	bob := New("Bob")
+	if err := s.Add(bob); err != nil {
+		t.Fatal(err)
+	}
+	if alice.ID == "" || alice.ID == bob.ID {
+		t.Errorf("identifiants invalides: %q et %q", alice.ID, bob.ID)
+	}
+	s.Last = bob.ID
+	s.Delete(bob)
+	if len(s.Profiles) != 1 || s.LastUsed() != nil {
+		t.Errorf("le profil supprimé est encore présent")
+	}
+}
+
+func TestRecordGame(t *testing.T) {
+	p := New("Alice")
+	if !p.RecordGame(GameResult{Mode: "Classique", Difficulty: "Normal", Score: 12, Duration: time.Minute}) {
+		t.Error("le premier score devrait être un record")
+	}
+	if p.RecordGame(GameResult{Mode: "Classique", Difficulty: "Normal", Score: 8, Duration: time.Minute}) {
+		t.Error("un score plus bas n'est pas un record")
+	}
+	if got := p.Best("Classique", "Normal"); got != 12 {
+		t.Errorf("meilleur score: obtenu %d, attendu 12", got)
+	}
+	if p.Best("Challenge", "Normal") != 0 {
+		t.Error("les records sont séparés par mode")
+	}
+	if p.GamesPlayed != 2 || p.PlayTime != 2*time.Minute {
+		t.Errorf("statistiques: %d parties en %v", p.GamesPlayed, p.PlayTime)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	s := Load(path)
+	p := New("Alice")
+	p.Controls = Letters
+	p.Color = 2
+	if err := s.Add(p); err != nil {
+		t.Fatal(err)
+	}
+	p.RecordGame(GameResult{Mode: "Challenge", Difficulty: "Difficile", Score: 30, Duration: time.Second})
+	s.Last = p.ID
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := Load(path).LastUsed()
+	if loaded == nil {
+		t.Fatal("dernier profil non retrouvé")
+	}
+	if loaded.Name != "Alice" || loaded.Controls != Letters || loaded.Color != 2 || loaded.Best("Challenge", "Difficile") != 30 {
+		t.Errorf("profil relu différent: %+v", loaded)
+	}
+}
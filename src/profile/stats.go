@@ -0,0 +1,136 @@
+package profile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Découpage de l'histogramme des scores : des tranches de HistogramStep points, la dernière regroupe les scores plus élevés
+const (
+	HistogramStep    = 5
+	HistogramBuckets = 10
+)
+
+// Stats regroupe les statistiques cumulées d'un profil sur toutes ses parties
+type Stats struct {
+	ApplesEaten  int            `json:"apples_eaten"`
+	LongestSnake int            `json:"longest_snake"`
+	Distance     int            `json:"distance"`         // nombre de cases parcourues
+	Deaths       map[string]int `json:"deaths,omitempty"` // nombre de morts par cause, vies perdues comprises
+	Scores       []int          `json:"scores,omitempty"` // nombre de parties par tranche de score, voir HistogramStep
+}
+
+// GameResult décrit une partie terminée
+type GameResult struct {
+	Mode       string
+	Difficulty string
+	Score      int
+	Apples     int
+	Distance   int
+	Length     int // longueur maximale du serpent pendant la partie
+	Duration   time.Duration
+	Deaths     []string // causes des morts, une par vie perdue
+	Assisted   bool     // partie jouée avec l'assistance à la vitesse, qui ne peut pas devenir un record
+}
+
+// RecordGame ajoute une partie terminée aux statistiques du profil
+// Retourne true si le score est un nouveau record pour ce mode et cette difficulté
+// Une partie assistée compte dans les statistiques mais pas dans les records
+func (p *Profile) RecordGame(result GameResult) bool {
+	p.GamesPlayed++
+	p.PlayTime += result.Duration
+
+	s := &p.Stats
+	s.ApplesEaten += result.Apples
+	s.Distance += result.Distance
+	s.LongestSnake = max(s.LongestSnake, result.Length)
+	for _, cause := range result.Deaths {
+		if s.Deaths == nil {
+			s.Deaths = map[string]int{}
+		}
+		s.Deaths[cause]++
+	}
+	if len(s.Scores) < HistogramBuckets {
+		s.Scores = append(s.Scores, make([]int, HistogramBuckets-len(s.Scores))...)
+	}
+	s.Scores[min(max(result.Score, 0)/HistogramStep, HistogramBuckets-1)]++
+
+	key := BestKey(result.Mode, result.Difficulty)
+	if result.Assisted || result.Score <= p.Bests[key] {
+		return false
+	}
+	if p.Bests == nil {
+		p.Bests = map[string]int{}
+	}
+	p.Bests[key] = result.Score
+	return true
+}
+
+// AverageGame retourne la durée moyenne d'une partie du profil
+func (p *Profile) AverageGame() time.Duration {
+	if p.GamesPlayed == 0 {
+		return 0
+	}
+	return p.PlayTime / time.Duration(p.GamesPlayed)
+}
+
+// HistogramLabel retourne le libellé d'une tranche de l'histogramme des scores, "0-4", "5-9"... puis "45+"
+func HistogramLabel(bucket int) string {
+	if bucket == HistogramBuckets-1 {
+		return fmt.Sprintf("%d+", bucket*HistogramStep)
+	}
+	return fmt.Sprintf("%d-%d", bucket*HistogramStep, (bucket+1)*HistogramStep-1)
+}
+
+// causes des morts triées, pour un export stable
+func (s Stats) deathCauses() []string {
+	causes := make([]string, 0, len(s.Deaths))
+	for cause := range s.Deaths {
+		causes = append(causes, cause)
+	}
+	sort.Strings(causes)
+	return causes
+}
+
+// ExportJSON écrit le profil et ses statistiques au format JSON
+func (p *Profile) ExportJSON(w io.Writer) error {
+	export := struct {
+		*Profile
+		AverageGame time.Duration `json:"average_game"`
+	}{p, p.AverageGame()}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(export)
+}
+
+// ExportCSV écrit les statistiques du profil au format CSV, une ligne "statistique,valeur" par valeur
+func (p *Profile) ExportCSV(w io.Writer) error {
+	s := p.Stats
+	rows := [][]string{
+		{"stat", "value"},
+		{"name", p.Name},
+		{"games_played", strconv.Itoa(p.GamesPlayed)},
+		{"play_time_seconds", strconv.Itoa(int(p.PlayTime.Seconds()))},
+		{"average_game_seconds", strconv.Itoa(int(p.AverageGame().Seconds()))},
+		{"apples_eaten", strconv.Itoa(s.ApplesEaten)},
+		{"longest_snake", strconv.Itoa(s.LongestSnake)},
+		{"distance", strconv.Itoa(s.Distance)},
+	}
+	for _, cause := range s.deathCauses() {
+		rows = append(rows, []string{"deaths_" + cause, strconv.Itoa(s.Deaths[cause])})
+	}
+	for bucket, count := range s.Scores {
+		rows = append(rows, []string{"scores_" + HistogramLabel(bucket), strconv.Itoa(count)})
+	}
+
+	out := csv.NewWriter(w)
+	if err := out.WriteAll(rows); err != nil {
+		return err
+	}
+	return out.Error()
+}
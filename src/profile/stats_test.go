@@ -0,0 +1,78 @@
+package profile
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	p := New("Alice")
+	p.RecordGame(GameResult{Mode: "Challenge", Difficulty: "Facile", Score: 7, Apples: 7, Distance: 120, Length: 9, Duration: time.Minute, Deaths: []string{"wall", "self"}})
+	p.RecordGame(GameResult{Mode: "Classique", Difficulty: "Facile", Score: 80, Apples: 80, Distance: 900, Length: 82, Duration: 3 * time.Minute, Deaths: []string{"wall"}})
+
+	s := p.Stats
+	if s.ApplesEaten != 87 || s.Distance != 1020 || s.LongestSnake != 82 {
+		t.Errorf("cumuls incorrects: %+v", s)
+	}
+	if s.Deaths["wall"] != 2 || s.Deaths["self"] != 1 {
+		t.Errorf("morts par cause incorrectes: %v", s.Deaths)
+	}
+	if s.Scores[1] != 1 || s.Scores[HistogramBuckets-1] != 1 {
+		t.Errorf("histogramme incorrect: %v", s.Scores)
+	}
+	if got := p.AverageGame(); got != 2*time.Minute {
+		t.Errorf("durée moyenne: obtenu %v, attendu 2m", got)
+	}
+	if HistogramLabel(1) != "5-9" || HistogramLabel(HistogramBuckets-1) != "45+" {
+		t.Errorf("libellés de l'histogramme: %q, %q", HistogramLabel(1), HistogramLabel(HistogramBuckets-1))
+	}
+}
+
+func TestAssistedGameIsNotRecord(t *testing.T) {
+	p := New("Alice")
+	if p.RecordGame(GameResult{Mode: "Classique", Difficulty: "Normal", Score: 12, Apples: 12, Assisted: true}) {
+		t.Error("une partie assistée ne devrait pas être un record")
+	}
+	if best := p.Bests[BestKey("Classique", "Normal")]; best != 0 {
+		t.Errorf("record %d enregistré pour une partie assistée", best)
+	}
+	if p.GamesPlayed != 1 || p.Stats.ApplesEaten != 12 {
+		t.Errorf("la partie assistée devrait compter dans les statistiques: %d parties, %d pommes", p.GamesPlayed, p.Stats.ApplesEaten)
+	}
+}
+
+func TestExport(t *testing.T) {
+	p := New("Alice")
+	p.RecordGame(GameResult{Mode: "Classique", Difficulty: "Normal", Score: 3, Apples: 3, Deaths: []string{"obstacle"}})
+
+	var buf bytes.Buffer
+	if err := p.ExportCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]string{}
+	for _, row := range rows {
+		values[row[0]] = row[1]
+	}
+	if values["apples_eaten"] != "3" || values["deaths_obstacle"] != "1" || values["scores_0-4"] != "1" {
+		t.Errorf("export CSV incomplet: %v", values)
+	}
+
+	buf.Reset()
+	if err := p.ExportJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var exported map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &exported); err != nil {
+		t.Fatal(err)
+	}
+	if exported["name"] != "Alice" || exported["stats"] == nil {
+		t.Errorf("export JSON incomplet: %v", exported)
+	}
+}
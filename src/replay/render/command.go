@@ -0,0 +1,106 @@
+package render
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"snake-go/src/capture"
+	"snake-go/src/config"
+	"snake-go/src/i18n"
+	"snake-go/src/replay"
+	"snake-go/src/resources"
+	"snake-go/src/theme"
+)
+
+// Command lit les options de la ligne de commande, rejoue la partie donnée et l'enregistre en GIF,
+// ou en PNG animé si le fichier de sortie se termine par .png
+// Elle sert la sous-commande render du jeu et la commande cmd/render, qui fonctionne sans écran
+//
+// name: le nom de la commande, affiché dans l'aide
+// args: les arguments qui suivent la commande, la partie peut être donnée avant ou après les options
+func Command(name string, args []string) {
+	cfg := config.Load()
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Utilisation: %s [options] partie.json\n", name)
+		fs.PrintDefaults()
+	}
+	output := fs.String("o", "", "fichier de sortie, .gif ou .png (par défaut le nom de la partie en .gif)")
+	width := fs.Int("width", 480, "largeur des images, en pixels")
+	height := fs.Int("height", 480, "hauteur des images, en pixels")
+	fps := fs.Int("fps", 20, "images par seconde (60 au plus)")
+	last := fs.Int("last", 0, "ne garder que les dernières secondes de la partie, toute la partie si 0")
+	themeID := fs.String("theme", "", "thème utilisé pour les sprites (par défaut celui de la partie)")
+	fs.StringVar(&cfg.AssetsDir, "assets", cfg.AssetsDir, "dossier de ressources remplaçant les ressources embarquées")
+	fs.StringVar(&cfg.Language, "lang", cfg.Language, "langue du hud")
+
+	// la partie peut être donnée avant ou après les options
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	path := fs.Arg(0)
+	fs.Parse(fs.Args()[1:])
+	if fs.NArg() > 0 || *width <= 0 || *height <= 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *output == "" {
+		*output = strings.TrimSuffix(path, filepath.Ext(path)) + ".gif"
+	}
+
+	r, err := replay.Load(path)
+	if err != nil {
+		log.Fatalf("Impossible de lire la partie %s: %v", path, err)
+	}
+	// le thème de la partie, puis celui de la configuration s'il n'a pas été enregistré
+	if *themeID == "" {
+		*themeID = r.Start.Theme
+	}
+	if *themeID == "" {
+		*themeID = cfg.Theme
+	}
+	resources.Init(cfg.AssetsDir)
+	theme.Init(config.ThemesDir(), *themeID)
+	i18n.LoadDir(config.LocalesDir())
+	i18n.SetLanguage(cfg.Language)
+
+	opts := Options{Width: *width, Height: *height, FPS: min(*fps, 60), Last: *last}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// les images sont encodées au fur et à mesure, sans garder toute la partie en mémoire
+	var out interface {
+		Add(frame *image.RGBA) error
+		Close() error
+	}
+	if strings.EqualFold(filepath.Ext(*output), ".png") {
+		out = capture.NewAPNGWriter(file, Count(r, opts), opts.Delay())
+	} else {
+		out = capture.NewGIFWriter(file, opts.Delay())
+	}
+	count := 0
+	err = Replay(r, opts, func(frame *image.RGBA) error {
+		count++
+		return out.Add(frame)
+	})
+	if err == nil {
+		err = out.Close()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(*output)
+		log.Fatalf("Impossible d'enregistrer %s: %v", *output, err)
+	}
+	log.Printf("%d images enregistrées dans %s", count, *output)
+}
@@ -0,0 +1,237 @@
+// Package render rejoue les parties enregistrées hors du jeu et les dessine en mémoire, sans fenêtre
+// ni carte graphique : il n'importe pas ebiten et fonctionne sans écran
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"log"
+	"math/rand"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"snake-go/src/board"
+	"snake-go/src/capture"
+	"snake-go/src/config"
+	"snake-go/src/event"
+	"snake-go/src/i18n"
+	"snake-go/src/profile"
+	"snake-go/src/replay"
+	"snake-go/src/theme"
+)
+
+// Cadence d'une partie rejouée hors du jeu
+const (
+	ticksPerSecond = 60 // la partie avance d'un tick par image du jeu
+	deathTicks     = 60 // l'animation de la dernière mort est montrée aussi longtemps que le ralenti du jeu (vfx.SlowMotionTicks)
+)
+
+// Options règle les images produites par Replay
+type Options struct {
+	Width, Height int // taille des images, en pixels
+	FPS           int // images par seconde, au plus une par tick
+	Last          int // secondes gardées à la fin de la partie, toute la partie si 0
+}
+
+// partie rejouée tick par tick, avec la graine et les virages enregistrés
+type player struct {
+	r           *replay.Replay
+	grid        *board.Grid
+	rng         *rand.Rand
+	bus         *event.Bus
+	lives       int
+	interval    int // ticks entre deux déplacements, réduit comme pendant la partie
+	count       int // ticks depuis le dernier déplacement
+	score       int
+	lastSpeedUp int // score de la dernière accélération
+	board       int // plateau en cours, c'est-à-dire le nombre de morts
+	turn        int // prochain virage à jouer
+	step        int // déplacements joués
+	ended       bool
+}
+
+// prépare le premier plateau d'une partie enregistrée
+func newPlayer(r *replay.Replay) *player {
+	p := &player{
+		r:        r,
+		rng:      rand.New(rand.NewSource(r.Start.Seed)),
+		bus:      event.NewBus(),
+		lives:    max(r.Start.Lives, 1),
+		interval: r.Start.Interval,
+	}
+	event.Subscribe(p.bus, func(e event.FoodEaten) { p.score++ })
+	p.grid = p.newGrid()
+	return p
+}
+
+// grille d'un plateau de la partie, avec l'apparence du serpent du joueur
+func (p *player) newGrid() *board.Grid {
+	start := p.r.Start
+	grid := board.NewGame(start.Mode, start.Difficulty, start.Width, start.Height, p.rng)
+	grid.Access = config.Default().Access
+	grid.Skin = theme.Find(start.Skin)
+	grid.Tint = (&profile.Profile{Skin: start.Skin, Color: start.Color}).Tint()
+	return grid
+}
+
+// avance la partie d'un tick, le serpent se déplace tous les interval ticks jusqu'à la dernière mort
+func (p *player) tick() {
+	p.grid.Animate()
+	if p.ended {
+		return
+	}
+	p.count++
+	if p.count < p.interval {
+		return
+	}
+	p.count = 0
+
+	// même accélération que pendant la partie, elle ne change pas le déroulement mais la cadence des images
+	if p.score > 0 && p.score%5 == 0 && p.score != p.lastSpeedUp {
+		p.interval = max(board.MinInterval(p.r.Start.Assist), p.interval-1)
+		p.lastSpeedUp = p.score
+	}
+	turns := len(p.r.Turns)
+	if p.board < len(p.r.Deaths) {
+		turns = p.r.Deaths[p.board]
+	}
+	if p.turn < turns && p.r.Turns[p.turn].Step == p.step {
+		p.grid.Turn(board.Direction(p.r.Turns[p.turn].Direction))
+		p.turn++
+	}
+	if err := p.grid.Step(p.bus); err == nil {
+		p.step++
+		return
+	}
+	p.board++
+	if p.lives > 1 {
+		p.lives--
+		p.grid = p.newGrid()
+	} else {
+		p.ended = true
+	}
+}
+
+// Simulate rejoue une partie enregistrée jusqu'à sa dernière mort, sans rien dessiner
+//
+// r: la partie enregistrée
+// Retourne le nombre de déplacements et le score de la partie rejouée, égaux à r.Steps et r.Score
+// si elle se déroule comme la partie enregistrée
+func Simulate(r *replay.Replay) (steps, score int) {
+	p := newPlayer(r)
+	for !p.ended {
+		p.tick()
+	}
+	return p.step, p.score
+}
+
+// nombre de ticks entre deux images
+func (o Options) every() int {
+	return max(ticksPerSecond/max(o.FPS, 1), 1)
+}
+
+// Delay retourne la durée d'une image en centièmes de seconde
+func (o Options) Delay() int {
+	return o.every() * 100 / ticksPerSecond
+}
+
+// nombre d'images gardées pour les dernières secondes de la partie
+func (o Options) lastFrames() int {
+	return o.Last*ticksPerSecond/o.every() + 1
+}
+
+// joue la partie jusqu'à la fin de l'animation de la dernière mort et appelle frame à chaque image à produire
+// Retourne la partie rejouée, ou l'erreur de frame qui l'a interrompue
+func play(r *replay.Replay, opts Options, frame func(p *player) error) (*player, error) {
+	every := opts.every()
+	p := newPlayer(r)
+	ended := -1 // tick de la dernière mort
+	for tick := 0; ended < 0 || tick-ended < deathTicks; tick++ {
+		p.tick()
+		if p.ended && ended < 0 {
+			ended = tick
+		}
+		if tick%every != 0 {
+			continue
+		}
+		if err := frame(p); err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
+
+// Count retourne le nombre d'images que Replay produira pour une partie, sans les dessiner
+func Count(r *replay.Replay, opts Options) int {
+	count := 0
+	play(r, opts, func(*player) error {
+		count++
+		return nil
+	})
+	if opts.Last > 0 {
+		count = min(count, opts.lastFrames())
+	}
+	return count
+}
+
+// Replay rejoue une partie enregistrée et dessine chaque image en mémoire avec les sprites du thème courant
+// Les images sont confiées une à une à add sans être gardées, sauf les dernières secondes demandées par opts.Last
+//
+// r: la partie enregistrée
+// opts: la taille et la cadence des images, dont la durée est donnée par opts.Delay
+// add: reçoit les images de la plus ancienne à la plus récente, l'image est réutilisée après l'appel
+// Retourne l'erreur de add qui a interrompu le rendu
+func Replay(r *replay.Replay, opts Options, add func(frame *image.RGBA) error) error {
+	// seules les dernières secondes sont gardées si elles sont demandées
+	var clip *capture.Clip
+	if opts.Last > 0 {
+		clip = capture.NewClip(opts.lastFrames())
+	}
+	frame := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+
+	p, err := play(r, opts, func(p *player) error {
+		draw.Draw(frame, frame.Bounds(), image.NewUniform(theme.Current().Palette.Screen), image.Point{}, draw.Src)
+		p.grid.Render(frame)
+		p.renderHUD(frame)
+		if clip != nil {
+			clip.Add(frame)
+			return nil
+		}
+		return add(frame)
+	})
+	if err != nil {
+		return err
+	}
+
+	if p.step != r.Steps || p.score != r.Score {
+		log.Printf("Attention: la partie rejouée diffère de l'enregistrement (%d déplacements et %d points au lieu de %d et %d)",
+			p.step, p.score, r.Steps, r.Score)
+	}
+	if clip != nil {
+		for _, kept := range clip.Frames() {
+			if err := add(kept); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dessine le score et les vies comme le hud de la partie, sans les cœurs
+func (p *player) renderHUD(dst draw.Image) {
+	drawer := &font.Drawer{Dst: dst, Face: basicfont.Face7x13}
+	write := func(s string, x, y int, c color.Color) {
+		drawer.Src = image.NewUniform(c)
+		drawer.Dot = fixed.P(x, y)
+		drawer.DrawString(s)
+	}
+	textColor := theme.Current().Palette.Text
+	write(i18n.T("hud.score", p.score), 10, 20, textColor)
+	write(i18n.N("hud.lives", p.lives), 10, 50, color.RGBA{255, 0, 0, 255})
+	if p.r.Start.Assist {
+		write(i18n.T("hud.assist"), 10, 80, textColor)
+	}
+}
@@ -0,0 +1,121 @@
+package render
+
+import (
+	"image"
+	"math/rand"
+	"testing"
+
+	"snake-go/src/board"
+	"snake-go/src/event"
+	"snake-go/src/replay"
+	"snake-go/src/resources"
+	"snake-go/src/theme"
+)
+
+// joue une partie du mode Challenge avec un serpent qui va droit vers la pomme et l'enregistre
+func record(t *testing.T) *replay.Replay {
+	t.Helper()
+	recorder := &replay.Recorder{Dir: t.TempDir()}
+	bus := event.NewBus()
+	recorder.Subscribe(bus)
+
+	start := event.GameStarted{Mode: board.ChallengeMode, Difficulty: "Normal", Width: 20, Height: 20, Seed: 7, Lives: 2, Interval: 4}
+	score := 0
+	var head image.Point
+	event.Subscribe(bus, func(e event.FoodEaten) { score++ })
+	event.Subscribe(bus, func(e event.Moved) { head = e.Head })
+	event.Publish(bus, start)
+
+	rng := rand.New(rand.NewSource(start.Seed))
+	for lives := start.Lives; lives > 0; lives-- {
+		grid := board.NewGame(start.Mode, start.Difficulty, start.Width, start.Height, rng)
+		head = image.Pt(start.Width/2, start.Height/2)
+		// le serpent finit par aller tout droit dans un mur
+		for steps := 0; ; steps++ {
+			if food := grid.Food(); steps < 300 {
+				switch {
+				case food.X > head.X:
+					grid.Turn(board.Right)
+				case food.X < head.X:
+					grid.Turn(board.Left)
+				case food.Y > head.Y:
+					grid.Turn(board.Down)
+				default:
+					grid.Turn(board.Up)
+				}
+			}
+			if grid.Step(bus) != nil {
+				break
+			}
+		}
+	}
+	event.Publish(bus, event.GameEnded{Score: score})
+
+	r, err := replay.Load(recorder.Last)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Deaths) != 2 || r.Score == 0 {
+		t.Fatalf("la partie enregistrée devrait avoir deux plateaux et des pommes mangées: %+v", r)
+	}
+	return r
+}
+
+func TestSimulate(t *testing.T) {
+	r := record(t)
+	steps, score := Simulate(r)
+	if steps != r.Steps || score != r.Score {
+		t.Errorf("partie rejouée avec %d déplacements et %d points au lieu de %d et %d", steps, score, r.Steps, r.Score)
+	}
+}
+
+func TestReplayLast(t *testing.T) {
+	resources.Init("")
+	theme.Init("", "")
+	r := record(t)
+
+	opts := Options{Width: 64, Height: 48, FPS: 10, Last: 1}
+	if delay := opts.Delay(); delay != 10 {
+		t.Errorf("durée des images: %d centièmes au lieu de 10", delay)
+	}
+	count := 0
+	err := Replay(r, opts, func(frame *image.RGBA) error {
+		count++
+		if frame.Rect != image.Rect(0, 0, 64, 48) {
+			t.Fatalf("taille des images: %v", frame.Rect)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 11 || Count(r, opts) != count {
+		t.Errorf("%d images gardées pour la dernière seconde, %d annoncées, au lieu de 11", count, Count(r, opts))
+	}
+}
+
+func TestReplayStreamsFrames(t *testing.T) {
+	resources.Init("")
+	theme.Init("", "")
+	r := record(t)
+
+	// sans -last, chaque image est confiée à l'encodeur dès qu'elle est dessinée, dans la même image réutilisée
+	opts := Options{Width: 32, Height: 32, FPS: 20}
+	var first *image.RGBA
+	count := 0
+	err := Replay(r, opts, func(frame *image.RGBA) error {
+		if first == nil {
+			first = frame
+		} else if frame != first {
+			t.Fatal("chaque image ne devrait pas être gardée dans une nouvelle image")
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Count(r, opts); count != want || count < r.Steps {
+		t.Errorf("%d images produites, %d annoncées pour %d déplacements", count, want, r.Steps)
+	}
+}
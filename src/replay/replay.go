@@ -0,0 +1,139 @@
+// Package replay enregistre les parties à partir de leurs événements, pour pouvoir les rejouer
+//
+// Une partie est entièrement déterminée par la graine de son plateau et les virages du serpent :
+// chaque virage est enregistré avec le numéro du déplacement pendant lequel il a lieu.
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"snake-go/src/config"
+	"snake-go/src/event"
+)
+
+// Version du format des fichiers de parties
+// La version 2 ajoute les morts : sans elles, les virages d'une partie à plusieurs vies ne peuvent pas être
+// rejoués à l'identique, les fichiers de la version 1 sont refusés
+const Version = 2
+
+// MaxReplays est le nombre de parties gardées dans le dossier des enregistrements, les plus anciennes sont supprimées
+const MaxReplays = 50
+
+// Turn est un virage du serpent
+type Turn struct {
+	Step      int `json:"step"` // numéro du déplacement, depuis le début de la partie
+	Direction int `json:"direction"`
+}
+
+// Replay est une partie enregistrée
+// Un virage pris au moment d'une collision a le même numéro de déplacement que le premier déplacement du plateau
+// suivant : Deaths indique à quel plateau appartient chaque virage
+type Replay struct {
+	Version int               `json:"version"`
+	Date    time.Time         `json:"date"`
+	Start   event.GameStarted `json:"start"`
+	Turns   []Turn            `json:"turns"`
+	Steps   int               `json:"steps"` // nombre total de déplacements
+	Score   int               `json:"score"`
+	Deaths  []int             `json:"deaths"` // nombre de virages enregistrés à chaque mort
+}
+
+// Load lit une partie enregistrée
+func Load(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Replay
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	if r.Version != Version {
+		return nil, fmt.Errorf("version %d du fichier non prise en charge", r.Version)
+	}
+	// un fichier abîmé ou modifié ne doit pas faire planter la partie rejouée
+	if r.Start.Interval <= 0 {
+		return nil, fmt.Errorf("intervalle de déplacement %d invalide", r.Start.Interval)
+	}
+	r.Start.Width = config.ClampBoardSize(r.Start.Width)
+	r.Start.Height = config.ClampBoardSize(r.Start.Height)
+	return &r, nil
+}
+
+// Save enregistre la partie dans un fichier
+func (r *Replay) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Recorder construit l'enregistrement de la partie en cours à partir des événements publiés
+// et l'enregistre dans Dir à la fin de la partie
+type Recorder struct {
+	Dir     string // dossier des enregistrements, rien n'est enregistré si vide
+	current *Replay
+	Last    string // fichier de la dernière partie enregistrée
+}
+
+// Subscribe abonne l'enregistreur aux événements d'une partie
+func (r *Recorder) Subscribe(bus *event.Bus) {
+	event.Subscribe(bus, func(e event.GameStarted) {
+		r.current = &Replay{Version: Version, Date: time.Now(), Start: e}
+	})
+	event.Subscribe(bus, func(e event.Turned) {
+		if r.current != nil {
+			r.current.Turns = append(r.current.Turns, Turn{Step: r.current.Steps, Direction: e.Direction})
+		}
+	})
+	event.Subscribe(bus, func(e event.Moved) {
+		if r.current != nil {
+			r.current.Steps++
+		}
+	})
+	event.Subscribe(bus, func(e event.Died) {
+		if r.current != nil {
+			r.current.Deaths = append(r.current.Deaths, len(r.current.Turns))
+		}
+	})
+	event.Subscribe(bus, func(e event.GameEnded) {
+		if r.current == nil {
+			return
+		}
+		r.current.Score = e.Score
+		r.save()
+		r.current = nil
+	})
+}
+
+// enregistre la partie terminée puis supprime les plus anciennes au-delà de MaxReplays
+func (r *Recorder) save() {
+	if r.Dir == "" {
+		return
+	}
+	path := filepath.Join(r.Dir, r.current.Date.Format("20060102-150405")+".json")
+	if err := r.current.Save(path); err != nil {
+		log.Printf("Impossible d'enregistrer la partie: %v", err)
+		return
+	}
+	r.Last = path
+
+	files, err := filepath.Glob(filepath.Join(r.Dir, "*.json"))
+	if err != nil || len(files) <= MaxReplays {
+		return
+	}
+	sort.Strings(files) // les noms commencent par la date
+	for _, file := range files[:len(files)-MaxReplays] {
+		os.Remove(file)
+	}
+}
@@ -0,0 +1,80 @@
+package replay
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"snake-go/src/constants"
+	"snake-go/src/event"
+)
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	bus := event.NewBus()
+	recorder := &Recorder{Dir: dir}
+	recorder.Subscribe(bus)
+
+	event.Publish(bus, event.GameStarted{Mode: "Classique", Difficulty: "Normal", Width: 20, Height: 20, Seed: 42, Interval: 4})
+	event.Publish(bus, event.Moved{})
+	event.Publish(bus, event.Moved{})
+	event.Publish(bus, event.Turned{Direction: 0})
+	event.Publish(bus, event.Moved{})
+	event.Publish(bus, event.Turned{Direction: 2}) // virage au moment de la collision
+	event.Publish(bus, event.Died{Cause: "wall"})
+	event.Publish(bus, event.Turned{Direction: 0}) // premier déplacement du plateau suivant
+	event.Publish(bus, event.Moved{})
+	event.Publish(bus, event.Died{Cause: "wall"})
+	event.Publish(bus, event.GameEnded{Score: 3})
+
+	if recorder.Last == "" {
+		t.Fatal("la partie n'a pas été enregistrée")
+	}
+	r, err := Load(recorder.Last)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Start.Seed != 42 || r.Steps != 4 || r.Score != 3 {
+		t.Errorf("enregistrement incorrect: %+v", r)
+	}
+	if len(r.Turns) != 3 || r.Turns[0] != (Turn{Step: 2, Direction: 0}) || r.Turns[1].Step != r.Turns[2].Step {
+		t.Errorf("virages incorrects: %v", r.Turns)
+	}
+	if len(r.Deaths) != 2 || r.Deaths[0] != 2 || r.Deaths[1] != 3 {
+		t.Errorf("les morts devraient séparer les virages de chaque plateau: %v", r.Deaths)
+	}
+}
+
+func TestLoadRejectsOldVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ancienne.json")
+	old := `{"version": 1, "start": {"Seed": 42}, "turns": [{"step": 2, "direction": 0}], "steps": 4, "score": 3}`
+	if err := os.WriteFile(path, []byte(old), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("une partie de la version 1, sans les morts, devrait être refusée")
+	}
+}
+
+func TestLoadValidatesStart(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	r, err := Load(write("plateau.json", `{"version": 2, "start": {"Width": 0, "Height": 1000, "Interval": 4}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Start.Width != constants.MinBoardSize || r.Start.Height != constants.MaxBoardSize {
+		t.Errorf("dimensions %d×%d, attendu %d×%d", r.Start.Width, r.Start.Height, constants.MinBoardSize, constants.MaxBoardSize)
+	}
+
+	if _, err := Load(write("intervalle.json", `{"version": 2, "start": {"Width": 20, "Height": 20, "Interval": 0}}`)); err == nil {
+		t.Error("une partie sans intervalle de déplacement devrait être refusée")
+	}
+}
@@ -1,47 +1,167 @@
 package resources
 
 import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/png"
+	"io/fs"
 	"log"
+	"os"
 
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"snake-go/assets"
 )
 
-// Variables globales pour les ressources
+// Images du jeu, décodées : l'interface les convertit en images ebiten au premier affichage,
+// les rendus hors jeu les dessinent directement
 var (
-	BackgroundImage *ebiten.Image
-	HeartImage      *ebiten.Image
-	SnakeSprite     *ebiten.Image
-	RKeyImage	   *ebiten.Image
-	EnterKeyImage  *ebiten.Image
+	BackgroundImage image.Image
+	HeartImage      image.Image
+	SnakeSprite     image.Image // planche de sprites des thèmes qui n'en ont pas
+	RKeyImage       image.Image
+	EnterKeyImage   image.Image
+	IconImage       image.Image
 )
 
-func init() {
-	var err error
+// dossier optionnel dont les fichiers remplacent les ressources embarquées
+var overrideDir fs.FS
 
-	BackgroundImage, _, err = ebitenutil.NewImageFromFile("assets/menu_background.png")
-	if err != nil {
-		log.Fatal(err)
+// Init charge toutes les images du jeu
+// Les fichiers présents dans overridePath remplacent ceux embarqués dans le binaire, une image introuvable est remplacée par une image générée
+//
+// overridePath: dossier de ressources personnalisées, ignoré s'il est vide
+func Init(overridePath string) {
+	if overridePath != "" {
+		if info, err := os.Stat(overridePath); err != nil || !info.IsDir() {
+			log.Printf("Attention: dossier de ressources %q introuvable, utilisation des ressources embarquées", overridePath)
+		} else {
+			overrideDir = os.DirFS(overridePath)
+		}
 	}
 
-	HeartImage, _, err = ebitenutil.NewImageFromFile("assets/coeur.png")
+	BackgroundImage = loadImage("menu_background.png", placeholderBackground)
+	HeartImage = loadImage("coeur.png", placeholderHeart)
+	SnakeSprite = loadImage("snake-sprite.png", placeholderSnakeSprite)
+	RKeyImage = loadImage("press-r.png", placeholderKey)
+	EnterKeyImage = loadImage("press-enter.png", placeholderKey)
+
+	icon, err := decodeImage("icone.png")
 	if err != nil {
-		log.Fatal(err)
+		log.Printf("Attention: icône indisponible: %v", err)
+		icon = placeholderHeart()
 	}
+	IconImage = icon
+}
 
-	SnakeSprite, _, err = ebitenutil.NewImageFromFile("assets/snake-sprite.png")
-	if err != nil {
-		log.Fatal(err)
+// Open ouvre une ressource, d'abord dans le dossier personnalisé puis dans les ressources embarquées
+//
+// name: le nom du fichier dans le dossier assets
+// Retourne le fichier ouvert ou une erreur s'il n'existe nulle part
+func Open(name string) (fs.File, error) {
+	if overrideDir != nil {
+		file, err := overrideDir.Open(name)
+		if err == nil {
+			return file, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Attention: impossible de lire %s dans le dossier de ressources: %v", name, err)
+		}
 	}
+	return assets.FS.Open(name)
+}
 
-	RKeyImage, _, err = ebitenutil.NewImageFromFile("assets/press-r.png")
+// ReadFile lit entièrement une ressource, avec la même priorité que Open
+//
+// name: le nom du fichier dans le dossier assets
+// Retourne le contenu du fichier
+func ReadFile(name string) ([]byte, error) {
+	if overrideDir != nil {
+		data, err := fs.ReadFile(overrideDir, name)
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Attention: impossible de lire %s dans le dossier de ressources: %v", name, err)
+		}
+	}
+	return assets.FS.ReadFile(name)
+}
+
+// décode une image depuis les ressources
+func decodeImage(name string) (image.Image, error) {
+	data, err := ReadFile(name)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
 
-	EnterKeyImage, _, err = ebitenutil.NewImageFromFile("assets/press-enter.png")
+// charge une image, ou génère une image de remplacement si elle est introuvable ou invalide
+//
+// name: le nom du fichier dans le dossier assets
+// fallback: la fonction qui génère l'image de remplacement
+// Retourne l'image décodée
+func loadImage(name string, fallback func() image.Image) image.Image {
+	img, err := decodeImage(name)
 	if err != nil {
-		log.Fatal(err)
+		log.Printf("Attention: image %s indisponible, utilisation d'une image générée: %v", name, err)
+		img = fallback()
+	}
+	return img
+}
+
+// fond uni pour les menus
+func placeholderBackground() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 9))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{R: 144, G: 190, B: 109, A: 255}), image.Point{}, draw.Src)
+	return img
+}
+
+// coeur remplacé par un carré rouge, dessiné à la même échelle que coeur.png
+func placeholderHeart() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 1000, 1000))
+	draw.Draw(img, image.Rect(100, 100, 900, 900), image.NewUniform(color.RGBA{R: 220, G: 30, B: 30, A: 255}), image.Point{}, draw.Src)
+	return img
+}
+
+// touche de clavier grise
+func placeholderKey() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{R: 90, G: 90, B: 90, A: 255}), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(2, 2, 18, 16), image.NewUniform(color.RGBA{R: 200, G: 200, B: 200, A: 255}), image.Point{}, draw.Src)
+	return img
+}
+
+// planche de sprites générée avec la même disposition que snake-sprite.png (5×8 cases de 64 pixels)
+func placeholderSnakeSprite() image.Image {
+	const tile = 64
+	img := image.NewRGBA(image.Rect(0, 0, 5*tile, 8*tile))
+
+	fillTile := func(x, y, inset int, c color.Color) {
+		rect := image.Rect(x*tile+inset, y*tile+inset, (x+1)*tile-inset, (y+1)*tile-inset)
+		draw.Draw(img, rect, image.NewUniform(c), image.Point{}, draw.Src)
 	}
 
+	body := color.RGBA{R: 80, G: 160, B: 60, A: 255}
+	head := color.RGBA{R: 40, G: 110, B: 30, A: 255}
+	// corps, virages et queues
+	for _, p := range []image.Point{{0, 0}, {1, 0}, {2, 0}, {0, 1}, {2, 1}, {2, 2}, {3, 2}, {4, 2}, {3, 3}, {4, 3}} {
+		fillTile(p.X, p.Y, 6, body)
+	}
+	// têtes, puis leurs images d'animation (yeux fermés, bouche ouverte, langue...)
+	for _, p := range []image.Point{{3, 0}, {4, 0}, {3, 1}, {4, 1}} {
+		fillTile(p.X, p.Y, 2, head)
+	}
+	for y := 4; y < 8; y++ {
+		for x := 0; x < 5; x++ {
+			fillTile(x, y, 2, head)
+		}
+	}
+	// pomme et obstacle
+	fillTile(0, 3, 12, color.RGBA{R: 210, G: 40, B: 40, A: 255})
+	fillTile(1, 3, 4, color.RGBA{R: 110, G: 110, B: 110, A: 255})
+	return img
 }
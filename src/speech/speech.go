@@ -0,0 +1,40 @@
+// Package speech lit des textes à voix haute avec la synthèse vocale du système, utilisée par la narration des menus
+package speech
+
+import (
+	"errors"
+	"os/exec"
+	"sync"
+)
+
+// ErrUnavailable est retournée quand aucune synthèse vocale n'est disponible sur ce système
+var ErrUnavailable = errors.New("synthèse vocale indisponible")
+
+var (
+	mu      sync.Mutex
+	current *exec.Cmd // lecture en cours, interrompue par la suivante
+)
+
+// Say lit un texte à voix haute sans attendre la fin de la lecture, en interrompant la lecture précédente
+//
+// text: le texte à lire
+// language: le code de la langue du texte (fr, en...), pour choisir la voix quand le système le permet
+func Say(text, language string) error {
+	cmd, err := command(text, language)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if current != nil {
+		current.Process.Kill()
+		current = nil
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	current = cmd
+	go cmd.Wait()
+	return nil
+}
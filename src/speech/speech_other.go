@@ -0,0 +1,35 @@
+//go:build !windows
+
+package speech
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// commandes de synthèse vocale essayées dans l'ordre, avec l'option qui choisit la langue
+var commands = []struct {
+	name     string
+	language string
+}{
+	{"espeak-ng", "-v"},
+	{"espeak", "-v"},
+	{"spd-say", "-l"},
+}
+
+func command(text, language string) (*exec.Cmd, error) {
+	if runtime.GOOS == "darwin" {
+		return exec.Command("say", "--", text), nil
+	}
+	for _, c := range commands {
+		if _, err := exec.LookPath(c.name); err != nil {
+			continue
+		}
+		args := []string{}
+		if language != "" {
+			args = append(args, c.language, language)
+		}
+		return exec.Command(c.name, append(args, "--", text)...), nil
+	}
+	return nil, ErrUnavailable
+}
@@ -0,0 +1,26 @@
+package speech
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// script PowerShell qui lit le texte avec System.Speech, le texte et la langue sont passés par l'environnement
+// pour ne pas avoir à les échapper
+const script = `Add-Type -AssemblyName System.Speech
+$s = New-Object System.Speech.Synthesis.SpeechSynthesizer
+if ($env:SNAKE_SPEECH_LANGUAGE) {
+	try { $s.SelectVoiceByHints('NotSet', 'NotSet', 0, [Globalization.CultureInfo]$env:SNAKE_SPEECH_LANGUAGE) } catch {}
+}
+$s.Speak($env:SNAKE_SPEECH_TEXT)`
+
+func command(text, language string) (*exec.Cmd, error) {
+	if _, err := exec.LookPath("powershell"); err != nil {
+		return nil, ErrUnavailable
+	}
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.Env = append(os.Environ(), "SNAKE_SPEECH_TEXT="+text, "SNAKE_SPEECH_LANGUAGE="+language)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	return cmd, nil
+}
@@ -0,0 +1,157 @@
+package theme
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+
+	"golang.org/x/image/math/f64"
+)
+
+// Frame est une image d'une animation : un élément de la planche de sprites, affiché un certain nombre de ticks
+type Frame struct {
+	Sprite   string     `json:"sprite"`   // nom de l'élément de la planche de sprites
+	Duration int        `json:"duration"` // durée d'affichage en ticks, au moins 1
+	Offset   [2]float64 `json:"offset"`   // décalage en pixels de la planche, pour un élément qui déborde de sa case
+	Scale    [2]float64 `json:"scale"`    // étirement horizontal et vertical autour du centre de la case, 1 si absent
+	Rotate   float64    `json:"rotate"`   // rotation en degrés autour du centre de la case
+	Opacity  float64    `json:"opacity"`  // opacité entre 0 et 1, 1 si absente
+}
+
+// Animation est une suite d'images jouée en boucle ou une seule fois
+type Animation struct {
+	Frames   []Frame `json:"frames"`
+	Loop     bool    `json:"loop"` // une animation qui ne boucle pas reste sur sa dernière image
+	duration int
+}
+
+// Duration retourne la durée totale de l'animation en ticks
+func (a *Animation) Duration() int {
+	return a.duration
+}
+
+// At retourne l'image affichée un certain nombre de ticks après le début de l'animation
+func (a *Animation) At(ticks int) Frame {
+	if a.Loop {
+		ticks %= a.duration
+		if ticks < 0 {
+			ticks += a.duration
+		}
+	}
+	for _, f := range a.Frames {
+		if ticks < f.Duration {
+			return f
+		}
+		ticks -= f.Duration
+	}
+	return a.Frames[len(a.Frames)-1]
+}
+
+// animations utilisées par les thèmes qui n'en définissent pas, pour la disposition de snake-sprite.png
+//
+//go:embed builtin/animations.json
+var defaultAnimationsJSON []byte
+
+// Animation retourne l'animation d'un élément du thème, ou nil si l'élément n'est pas animé
+//
+// name: le nom de l'animation (head_right, apple, head_right_eat...)
+func (t *Theme) Animation(name string) *Animation {
+	return t.animations[name]
+}
+
+// Frame retourne l'image d'un élément à afficher un certain nombre de ticks après le début de son animation
+// Un élément sans animation est son sprite fixe du même nom
+//
+// name: le nom de l'élément
+// ticks: le temps écoulé depuis le début de l'animation
+func (t *Theme) Frame(name string, ticks int) Frame {
+	if a := t.animations[name]; a != nil {
+		return a.At(ticks)
+	}
+	return Frame{Sprite: name, Duration: 1, Scale: [2]float64{1, 1}, Opacity: 1}
+}
+
+// Transform retourne le placement d'une image d'animation dans une case de l'écran : décalage, étirement
+// et rotation autour du centre de la case, puis mise à l'échelle de la case
+// La transformation s'applique aux coordonnées du sprite, dont le coin supérieur gauche est l'origine
+//
+// f: l'image d'animation, obtenue avec Frame
+// x, y: le coin supérieur gauche de la case à l'écran
+// cellSize: le côté de la case à l'écran, en pixels
+func (t *Theme) Transform(f Frame, x, y, cellSize float64) f64.Aff3 {
+	center := float64(t.TileSize) / 2
+	scale := cellSize / float64(t.TileSize)
+	sin, cos := math.Sincos(f.Rotate * math.Pi / 180)
+
+	m := f64.Aff3{1, 0, f.Offset[0] - center, 0, 1, f.Offset[1] - center}
+	m = multiply(f64.Aff3{f.Scale[0], 0, 0, 0, f.Scale[1], 0}, m)
+	m = multiply(f64.Aff3{cos, -sin, 0, sin, cos, 0}, m)
+	return multiply(f64.Aff3{scale, 0, scale*center + x, 0, scale, scale*center + y}, m)
+}
+
+// produit de deux transformations : b est appliquée d'abord, puis a
+func multiply(a, b f64.Aff3) f64.Aff3 {
+	return f64.Aff3{
+		a[0]*b[0] + a[1]*b[3], a[0]*b[1] + a[1]*b[4], a[0]*b[2] + a[1]*b[5] + a[2],
+		a[3]*b[0] + a[4]*b[3], a[3]*b[1] + a[4]*b[4], a[3]*b[2] + a[4]*b[5] + a[5],
+	}
+}
+
+// prépare les animations du thème, celles par défaut si le manifeste n'en définit pas
+// Une animation dont un sprite manque au thème est ignorée, avec un avertissement si le thème la définit lui-même
+func (t *Theme) loadAnimations() error {
+	defined := t.Animations != nil
+	animations := t.Animations
+	if !defined {
+		if err := json.Unmarshal(defaultAnimationsJSON, &animations); err != nil {
+			return fmt.Errorf("animations par défaut invalides: %w", err)
+		}
+	}
+
+	t.animations = make(map[string]*Animation, len(animations))
+	for name, a := range animations {
+		if missing := t.missingSprite(a); missing != "" {
+			if defined {
+				log.Printf("Attention: animation %q du thème %s ignorée, sprite %q manquant", name, t.ID, missing)
+			}
+			continue
+		}
+		a := a
+		a.Frames = append([]Frame(nil), a.Frames...)
+		for i := range a.Frames {
+			a.Frames[i].normalize()
+			a.duration += a.Frames[i].Duration
+		}
+		t.animations[name] = &a
+	}
+	return nil
+}
+
+// premier sprite d'une animation absent de la planche du thème, vide si tous existent
+func (t *Theme) missingSprite(a Animation) string {
+	if len(a.Frames) == 0 {
+		return "(aucune image)"
+	}
+	for _, f := range a.Frames {
+		if _, ok := t.Sprites[f.Sprite]; !ok {
+			return f.Sprite
+		}
+	}
+	return ""
+}
+
+// remplace les valeurs absentes du manifeste par celles d'une image fixe
+func (f *Frame) normalize() {
+	f.Duration = max(f.Duration, 1)
+	for i := range f.Scale {
+		if f.Scale[i] == 0 {
+			f.Scale[i] = 1
+		}
+	}
+	if f.Opacity == 0 {
+		f.Opacity = 1
+	}
+	f.Opacity = min(max(f.Opacity, 0), 1)
+}
@@ -0,0 +1,57 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+)
+
+// Color est une couleur écrite "#rrggbb" ou "#rrggbbaa" dans les manifestes
+type Color color.RGBA
+
+// RGBA permet d'utiliser Color partout où une color.Color est attendue
+func (c Color) RGBA() (r, g, b, a uint32) {
+	return color.RGBA(c).RGBA()
+}
+
+// UnmarshalJSON lit une couleur hexadécimale
+func (c *Color) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	var r, g, b uint8
+	a := uint8(255)
+	switch len(s) {
+	case 7:
+		_, err := fmt.Sscanf(s, "#%02x%02x%02x", &r, &g, &b)
+		if err != nil {
+			return fmt.Errorf("couleur %q invalide: %w", s, err)
+		}
+	case 9:
+		_, err := fmt.Sscanf(s, "#%02x%02x%02x%02x", &r, &g, &b, &a)
+		if err != nil {
+			return fmt.Errorf("couleur %q invalide: %w", s, err)
+		}
+	default:
+		return fmt.Errorf("couleur %q invalide, format attendu #rrggbb ou #rrggbbaa", s)
+	}
+
+	// color.RGBA attend des composantes prémultipliées par l'alpha
+	*c = Color{R: premultiply(r, a), G: premultiply(g, a), B: premultiply(b, a), A: a}
+	return nil
+}
+
+// MarshalJSON écrit la couleur au format hexadécimal
+func (c Color) MarshalJSON() ([]byte, error) {
+	n := color.NRGBAModel.Convert(color.RGBA(c)).(color.NRGBA)
+	if n.A == 255 {
+		return json.Marshal(fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B))
+	}
+	return json.Marshal(fmt.Sprintf("#%02x%02x%02x%02x", n.R, n.G, n.B, n.A))
+}
+
+func premultiply(v, a uint8) uint8 {
+	return uint8(uint32(v) * uint32(a) / 255)
+}
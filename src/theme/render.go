@@ -0,0 +1,41 @@
+package theme
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/draw"
+)
+
+// RenderFrame dessine une image d'animation comme ui.DrawFrame, mais sans carte graphique,
+// dans une image en mémoire : utilisée pour rejouer une partie hors du jeu
+//
+// dst: l'image sur laquelle dessiner
+// f: l'image d'animation, obtenue avec Frame
+// x, y, cellSize: la case à l'écran, comme pour Transform
+// tint: la couleur appliquée au sprite, aucune si nil
+func (t *Theme) RenderFrame(dst draw.Image, f Frame, x, y, cellSize float64, tint color.Color) {
+	rect, ok := t.Sprites[f.Sprite]
+	if !ok || t.Sheet == nil {
+		return
+	}
+	bounds := rect.Image().Add(t.Sheet.Bounds().Min)
+	sprite := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(sprite, sprite.Bounds(), t.Sheet, bounds.Min, draw.Src)
+
+	// même teinte et même opacité que ui.DrawFrame, appliquées aux composantes prémultipliées
+	scale := [4]float64{f.Opacity, f.Opacity, f.Opacity, f.Opacity}
+	if tint != nil {
+		r, g, b, a := tint.RGBA()
+		for i, c := range []uint32{r, g, b, a} {
+			scale[i] *= float64(c) / 0xffff
+		}
+	}
+	if scale != [4]float64{1, 1, 1, 1} {
+		for i := range sprite.Pix {
+			sprite.Pix[i] = uint8(float64(sprite.Pix[i]) * scale[i%4])
+		}
+	}
+
+	draw.NearestNeighbor.Transform(dst, t.Transform(f, x, y, cellSize), sprite, sprite.Bounds(), draw.Over, nil)
+}
@@ -0,0 +1,274 @@
+package theme
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"snake-go/src/resources"
+)
+
+//go:embed builtin/*.json
+var builtinFS embed.FS
+
+// ordre d'affichage des thèmes livrés avec le jeu
+var builtinNames = []string{"classic", "dark", "high-contrast", "retro"}
+
+// éléments que chaque planche de sprites doit définir
+var requiredSprites = []string{
+	"head_up", "head_down", "head_left", "head_right",
+	"tail_up", "tail_down", "tail_left", "tail_right",
+	"body_v", "body_h", "turn_ur", "turn_ul", "turn_dr", "turn_dl",
+	"apple", "obstacle",
+}
+
+// Manifest décrit un thème tel qu'il est écrit dans son fichier theme.json
+type Manifest struct {
+	Name        string          `json:"name"`
+	SpriteSheet string          `json:"sprite_sheet"`
+	TileSize    int             `json:"tile_size"`
+	Sprites     map[string]Rect `json:"sprites"`
+	Palette     Palette         `json:"palette"`
+	Font        string          `json:"font"` // vide pour utiliser la police de base
+	Sounds      Sounds          `json:"sounds"`
+	Background  string          `json:"background"` // vide pour remplir l'écran avec Palette.Screen
+	Tint        *Color          `json:"tint"`       // si présent, les sprites sont convertis en niveaux de cette couleur
+
+	Animations map[string]Animation `json:"animations"` // animations des éléments, celles des thèmes intégrés si absent
+}
+
+// Rect est un rectangle de la planche de sprites, écrit [x, y, largeur, hauteur] en pixels
+type Rect [4]int
+
+// Image retourne le rectangle sous forme d'image.Rectangle
+func (r Rect) Image() image.Rectangle {
+	return image.Rect(r[0], r[1], r[0]+r[2], r[1]+r[3])
+}
+
+// Palette regroupe les couleurs utilisées par le plateau et les écrans
+type Palette struct {
+	Screen         Color `json:"screen"`
+	Border         Color `json:"border"`
+	Board          Color `json:"board"`
+	Text           Color `json:"text"`
+	Accent         Color `json:"accent"`
+	Panel          Color `json:"panel"`
+	PanelText      Color `json:"panel_text"`
+	Highlight      Color `json:"highlight"`
+	CollisionColor Color `json:"collision"`
+}
+
+// Sounds donne le nom des fichiers de bruitages du thème
+type Sounds struct {
+	Move string `json:"move"`
+	Eat  string `json:"eat"`
+	Lose string `json:"lose"`
+}
+
+// Theme est un thème chargé, ses images sont décodées et converties par l'interface au premier affichage
+type Theme struct {
+	Manifest
+	ID              string
+	Sheet           image.Image // planche de sprites, déjà teintée si le thème a une teinte
+	BackgroundImage image.Image // nil si le thème n'a pas d'image de fond
+	dir             string      // dossier du thème, vide pour un thème intégré
+	animations      map[string]*Animation
+}
+
+// Variables globales
+var (
+	themes  []*Theme
+	current *Theme
+)
+
+// Init charge les thèmes intégrés puis ceux trouvés dans customDir et sélectionne le thème demandé
+//
+// customDir: dossier contenant un sous-dossier par thème personnalisé, ignoré s'il n'existe pas
+// selected: l'identifiant du thème à utiliser
+func Init(customDir string, selected string) {
+	themes = nil
+	for _, name := range builtinNames {
+		data, err := builtinFS.ReadFile("builtin/" + name + ".json")
+		if err != nil {
+			log.Printf("Attention: thème intégré %s introuvable: %v", name, err)
+			continue
+		}
+		t, err := load(name, data, "")
+		if err != nil {
+			log.Printf("Attention: thème intégré %s invalide: %v", name, err)
+			continue
+		}
+		themes = append(themes, t)
+	}
+
+	for _, t := range discover(customDir) {
+		themes = append(themes, t)
+	}
+
+	current = themes[0]
+	Select(selected)
+}
+
+// Current retourne le thème actuellement utilisé
+func Current() *Theme {
+	return current
+}
+
+// All retourne la liste des thèmes disponibles
+func All() []*Theme {
+	return themes
+}
+
+// Find retourne le thème qui a l'identifiant donné, ou nil
+func Find(id string) *Theme {
+	for _, t := range themes {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// Select change le thème utilisé, un identifiant inconnu est ignoré
+//
+// id: l'identifiant du thème
+// Retourne vrai si le thème a été trouvé
+func Select(id string) bool {
+	if t := Find(id); t != nil {
+		current = t
+		return true
+	}
+	return false
+}
+
+// Load charge un thème personnalisé depuis un dossier contenant un fichier theme.json
+//
+// dir: le dossier du thème
+// Retourne le thème chargé ou une erreur si le manifeste est absent ou invalide
+func Load(dir string) (*Theme, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "theme.json"))
+	if err != nil {
+		return nil, err
+	}
+	return load(filepath.Base(dir), data, dir)
+}
+
+// ReadFile lit un fichier du thème, d'abord dans son dossier puis dans les ressources du jeu
+//
+// name: le nom du fichier
+// Retourne le contenu du fichier
+func (t *Theme) ReadFile(name string) ([]byte, error) {
+	if t.dir != "" {
+		if data, err := os.ReadFile(filepath.Join(t.dir, name)); err == nil {
+			return data, nil
+		}
+	}
+	return resources.ReadFile(name)
+}
+
+// charge les thèmes personnalisés, un par sous-dossier de dir, triés par nom
+func discover(dir string) []*Theme {
+	if dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var found []*Theme
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		t, err := Load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			log.Printf("Attention: thème %s ignoré: %v", entry.Name(), err)
+			continue
+		}
+		found = append(found, t)
+	}
+	return found
+}
+
+// analyse un manifeste et charge les images du thème
+//
+// id: l'identifiant du thème
+// data: le contenu du fichier theme.json
+// dir: le dossier du thème, vide pour un thème intégré
+// Retourne le thème chargé
+func load(id string, data []byte, dir string) (*Theme, error) {
+	t := &Theme{ID: id, dir: dir}
+	if err := json.Unmarshal(data, &t.Manifest); err != nil {
+		return nil, fmt.Errorf("manifeste invalide: %w", err)
+	}
+	if t.Name == "" {
+		t.Name = id
+	}
+	if t.TileSize <= 0 {
+		return nil, fmt.Errorf("tile_size doit être positif")
+	}
+	for _, key := range requiredSprites {
+		if _, ok := t.Sprites[key]; !ok {
+			return nil, fmt.Errorf("sprite %q manquant", key)
+		}
+	}
+
+	sheet, err := t.decodeImage(t.SpriteSheet)
+	if err != nil {
+		log.Printf("Attention: planche de sprites du thème %s indisponible: %v", id, err)
+		sheet = resources.SnakeSprite
+	} else if t.Tint != nil {
+		sheet = tint(sheet, color.RGBA(*t.Tint))
+	}
+	t.Sheet = sheet
+	if err := t.loadAnimations(); err != nil {
+		return nil, err
+	}
+
+	if t.Background != "" {
+		background, err := t.decodeImage(t.Background)
+		if err != nil {
+			log.Printf("Attention: fond du thème %s indisponible: %v", id, err)
+		} else {
+			t.BackgroundImage = background
+		}
+	}
+	return t, nil
+}
+
+// décode une image du thème
+func (t *Theme) decodeImage(name string) (image.Image, error) {
+	data, err := t.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// convertit une image en niveaux d'une seule couleur, pour les thèmes monochromes
+func tint(src image.Image, c color.RGBA) image.Image {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(bounds)
+	draw.Draw(dst, bounds, src, bounds.Min, draw.Src)
+	for i := 0; i < len(dst.Pix); i += 4 {
+		r, g, b, a := dst.Pix[i], dst.Pix[i+1], dst.Pix[i+2], dst.Pix[i+3]
+		luminance := (299*uint32(r) + 587*uint32(g) + 114*uint32(b)) / 1000
+		// les pixels sont prémultipliés par l'alpha, la luminance l'est donc aussi
+		dst.Pix[i] = uint8(luminance * uint32(c.R) / 255)
+		dst.Pix[i+1] = uint8(luminance * uint32(c.G) / 255)
+		dst.Pix[i+2] = uint8(luminance * uint32(c.B) / 255)
+		dst.Pix[i+3] = a
+	}
+	return dst
+}
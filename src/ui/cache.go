@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/opentype"
+
+	"snake-go/src/theme"
+)
+
+// au-delà de ce nombre de panneaux en cache (redimensionnements successifs de la fenêtre), le cache est vidé
+const maxCachedPanels = 64
+
+type faceKey struct {
+	theme string
+	size  float64
+}
+
+type panelKey struct {
+	width, height int
+	color         color.RGBA
+}
+
+// Cache des ressources de l'interface, construites une seule fois puis réutilisées à chaque image
+var (
+	fonts  = map[string]*opentype.Font{} // police analysée de chaque thème
+	faces  = map[faceKey]font.Face{}
+	panels = map[panelKey]*ebiten.Image{}
+	images = map[image.Image]*ebiten.Image{} // images décodées des ressources et des thèmes
+)
+
+// Font retourne la police du thème à la taille demandée, agrandie à la taille de l'interface,
+// ou la police de base si elle est indisponible
+// La police n'est lue et analysée qu'une fois par thème, et chaque taille n'est créée qu'une fois
+func Font(size float64) font.Face {
+	return fontFace(size * scale)
+}
+
+// police du thème à la taille exacte demandée, sans tenir compte de la taille de l'interface
+func fontFace(size float64) font.Face {
+	currentTheme := theme.Current()
+	if currentTheme.Font == "" {
+		return basicfont.Face7x13
+	}
+
+	key := faceKey{theme: currentTheme.ID, size: size}
+	if face, ok := faces[key]; ok {
+		return face
+	}
+
+	face, err := newFace(currentTheme, size)
+	if err != nil {
+		log.Printf("Attention: police du thème %s indisponible, utilisation de la police de base: %v", currentTheme.ID, err)
+		face = basicfont.Face7x13
+	}
+	faces[key] = face
+	return face
+}
+
+// crée une taille de la police d'un thème à partir de la police analysée en cache
+func newFace(t *theme.Theme, size float64) (font.Face, error) {
+	tt, ok := fonts[t.ID]
+	if !ok {
+		fontBytes, err := t.ReadFile(t.Font)
+		if err != nil {
+			return nil, err
+		}
+		tt, err = opentype.Parse(fontBytes)
+		if err != nil {
+			return nil, err
+		}
+		fonts[t.ID] = tt
+	}
+
+	const dpi = 72
+	return opentype.NewFace(tt, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     dpi,
+		Hinting: font.HintingFull,
+	})
+}
+
+// Panel retourne une image unie de la taille et de la couleur demandées
+// L'image est créée au premier appel puis réutilisée, elle ne doit pas être modifiée
+//
+// width, height: la taille du panneau en pixels
+// c: la couleur de remplissage
+// Retourne l'image du panneau
+func Panel(width, height int, c color.Color) *ebiten.Image {
+	key := panelKey{width: max(width, 1), height: max(height, 1), color: color.RGBAModel.Convert(c).(color.RGBA)}
+	if panel, ok := panels[key]; ok {
+		return panel
+	}
+
+	if len(panels) >= maxCachedPanels {
+		for k, panel := range panels {
+			panel.Dispose()
+			delete(panels, k)
+		}
+	}
+
+	panel := ebiten.NewImage(key.width, key.height)
+	panel.Fill(key.color)
+	panels[key] = panel
+	return panel
+}
+
+// Image retourne une image décodée (ressource, planche de sprites ou fond d'un thème) prête à être dessinée
+// L'image ebiten est créée au premier appel puis réutilisée, l'image décodée ne doit plus être modifiée
+//
+// img: l'image décodée
+// Retourne l'image ebiten correspondante, nil si img est nil
+func Image(img image.Image) *ebiten.Image {
+	if img == nil {
+		return nil
+	}
+	if converted, ok := images[img]; ok {
+		return converted
+	}
+	converted := ebiten.NewImageFromImage(img)
+	images[img] = converted
+	return converted
+}
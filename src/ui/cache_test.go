@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+
+	"snake-go/src/board"
+	"snake-go/src/constants"
+	"snake-go/src/resources"
+	"snake-go/src/theme"
+)
+
+// testGame lance les tests dans une image d'ebiten, pour que les images puissent être dessinées
+type testGame struct {
+	m    *testing.M
+	code int
+}
+
+func (g *testGame) Update() error {
+	g.code = g.m.Run()
+	return ebiten.Termination
+}
+
+func (g *testGame) Draw(screen *ebiten.Image) {}
+
+func (g *testGame) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return constants.ScreenWidth, constants.ScreenHeight
+}
+
+func TestMain(m *testing.M) {
+	g := &testGame{m: m, code: 1}
+	if err := ebiten.RunGame(g); err != nil {
+		panic(err)
+	}
+	os.Exit(g.code)
+}
+
+// lit et analyse la police à chaque appel, comme le faisait RenderGameOver avant le cache
+func loadFontUncached(t *theme.Theme, size float64) (font.Face, error) {
+	fontBytes, err := t.ReadFile(t.Font)
+	if err != nil {
+		return nil, err
+	}
+
+	tt, err := opentype.Parse(fontBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return opentype.NewFace(tt, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+}
+
+// les cinq tailles de police utilisées par l'écran de fin de partie
+var gameOverFontSizes = []float64{70, 30, 25, 20, 20}
+
+func setupTheme(b *testing.B) {
+	b.Helper()
+	resources.Init("")
+	theme.Init("", "classic")
+}
+
+// Coût des polices d'une image de l'écran de fin de partie sans cache
+func BenchmarkGameOverFontsUncached(b *testing.B) {
+	setupTheme(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, size := range gameOverFontSizes {
+			if _, err := loadFontUncached(theme.Current(), size); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// Coût des polices d'une image de l'écran de fin de partie avec le cache
+func BenchmarkGameOverFontsCached(b *testing.B) {
+	setupTheme(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, size := range gameOverFontSizes {
+			Font(size)
+		}
+	}
+}
+
+// dessine la bordure et le fond du plateau de 20×20 cellules dans un écran de la taille par défaut
+func drawBoardPanels(b *testing.B, panel func(width, height int) *ebiten.Image) {
+	screen := ebiten.NewImage(constants.ScreenWidth, constants.ScreenHeight)
+	defer screen.Dispose()
+	layout := board.ComputeLayout(constants.ScreenWidth, constants.ScreenHeight, 20, 20)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, margin := range []int{constants.BorderThickness, 0} {
+			rect := layout.Rect(margin)
+			opts := &ebiten.DrawImageOptions{}
+			opts.GeoM.Translate(float64(rect.Min.X), float64(rect.Min.Y))
+			screen.DrawImage(panel(rect.Dx(), rect.Dy()), opts)
+		}
+	}
+}
+
+// Coût de la bordure et du fond du plateau de Grid.Draw quand ils étaient recréés à chaque image
+func BenchmarkBoardPanelsUncached(b *testing.B) {
+	setupTheme(b)
+	var created []*ebiten.Image
+	drawBoardPanels(b, func(width, height int) *ebiten.Image {
+		img := ebiten.NewImage(width, height)
+		img.Fill(theme.Current().Palette.Board)
+		created = append(created, img)
+		return img
+	})
+	b.StopTimer()
+	for _, img := range created {
+		img.Dispose()
+	}
+}
+
+// Coût de la bordure et du fond du plateau de Grid.Draw avec le cache des panneaux
+func BenchmarkBoardPanelsCached(b *testing.B) {
+	setupTheme(b)
+	drawBoardPanels(b, func(width, height int) *ebiten.Image {
+		return Panel(width, height, theme.Current().Palette.Board)
+	})
+}
+
+// Coût d'une image complète de l'écran de fin de partie, panneaux et polices en cache
+func BenchmarkRenderGameOver(b *testing.B) {
+	setupTheme(b)
+	screen := ebiten.NewImage(constants.ScreenWidth, constants.ScreenHeight)
+	defer screen.Dispose()
+	summary := GameOverSummary{Score: 12, Cause: "mur", Length: "15", PlayTime: 83 * time.Second, ApplesPerMinute: 8.7}
+	scores := []Score{{Value: 30, Name: "Joueur1"}, {Value: 23, Name: "Joueur2"}, {Value: 12, Name: "Joueur3"}}
+	RenderGameOver(screen, summary, scores) // crée les panneaux et les polices
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		RenderGameOver(screen, summary, scores)
+	}
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"math"
+
+	"snake-go/src/constants"
+)
+
+// taille de l'interface, 1 pour la taille normale
+var scale = 1.0
+
+// SetScale change la taille de l'interface : les polices et les dimensions des widgets sont agrandies d'autant
+// Une taille inférieure à 1 est ignorée
+func SetScale(s float64) {
+	scale = max(s, 1)
+}
+
+// Scale retourne la taille de l'interface, 1 pour la taille normale
+func Scale() float64 {
+	return scale
+}
+
+// Scaled agrandit une longueur de l'interface, en pixels, à la taille de l'interface
+func Scaled(n int) int {
+	return int(math.Round(float64(n) * scale))
+}
+
+// LogicalSize calcule la taille de l'écran logique à partir de la taille de la fenêtre
+// En dessous de la taille minimale, l'écran logique est agrandi en gardant le ratio de la fenêtre et ebiten le réduit à l'affichage
+// L'écran logique ne dépend pas de la taille de l'interface : les écrans sont tous prévus pour la taille minimale
+//
+// outsideWidth, outsideHeight: la taille de la fenêtre en pixels
+// Retourne la taille de l'écran logique
+func LogicalSize(outsideWidth, outsideHeight int) (int, int) {
+	width := float64(max(outsideWidth, 1))
+	height := float64(max(outsideHeight, 1))
+	factor := max(1, constants.MinScreenWidth/width, constants.MinScreenHeight/height)
+	return max(int(math.Round(width*factor)), constants.MinScreenWidth), max(int(math.Round(height*factor)), constants.MinScreenHeight)
+}
+
+// Letterbox retourne la plus grande zone au format de la fenêtre par défaut (16:9) qui tient dans l'écran
+// En plein écran, l'écran logique garde ce format et ebiten remplit le reste de l'écran de bandes noires
+//
+// outsideWidth, outsideHeight: la taille de l'écran en pixels
+// Retourne la taille de la zone affichée
+func Letterbox(outsideWidth, outsideHeight int) (int, int) {
+	width := min(outsideWidth, outsideHeight*constants.ScreenWidth/constants.ScreenHeight)
+	height := min(outsideHeight, outsideWidth*constants.ScreenHeight/constants.ScreenWidth)
+	return max(width, 1), max(height, 1)
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"testing"
+
+	"snake-go/src/constants"
+)
+
+func TestLogicalSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		wantW, wantH  int
+	}{
+		{"fenêtre par défaut", 1280, 720, 1280, 720},
+		{"plein écran", 1920, 1080, 1920, 1080},
+		{"petite fenêtre agrandie", 800, 600, 1024, 768},
+		{"fenêtre basse agrandie", 1280, 500, 1741, 680},
+		{"fenêtre réduite à rien", 0, 0, 1024, 1024},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, h := LogicalSize(tt.width, tt.height)
+			if w != tt.wantW || h != tt.wantH {
+				t.Errorf("LogicalSize(%d, %d) = %d×%d, attendu %d×%d", tt.width, tt.height, w, h, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+// la taille de l'interface agrandit les widgets, l'écran logique reste à la taille minimale ou au-dessus
+func TestScaled(t *testing.T) {
+	defer SetScale(1)
+	for _, s := range []float64{1, 1.25, 1.5, 2} {
+		SetScale(s)
+		if got, want := Scaled(24), int(24*s+0.5); got != want {
+			t.Errorf("Scaled(24) à %v = %d, attendu %d", s, got, want)
+		}
+		if w, h := LogicalSize(1280, 720); w < constants.MinScreenWidth || h < constants.MinScreenHeight {
+			t.Errorf("écran logique de %d×%d à %v, sous la taille minimale", w, h, s)
+		}
+	}
+	SetScale(0.5)
+	if Scale() != 1 {
+		t.Errorf("une taille inférieure à 100 %% devrait être ignorée, taille %v", Scale())
+	}
+}
+
+func TestLetterbox(t *testing.T) {
+	tests := []struct {
+		width, height int
+		wantW, wantH  int
+	}{
+		{1920, 1080, 1920, 1080},
+		{1920, 1200, 1920, 1080}, // bandes en haut et en bas
+		{2560, 1080, 1920, 1080}, // bandes à gauche et à droite
+		{1024, 768, 1024, 576},
+	}
+	for _, tt := range tests {
+		if w, h := Letterbox(tt.width, tt.height); w != tt.wantW || h != tt.wantH {
+			t.Errorf("Letterbox(%d, %d) = %d×%d, attendu %d×%d", tt.width, tt.height, w, h, tt.wantW, tt.wantH)
+		}
+	}
+}
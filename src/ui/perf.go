@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// nombre d'images sur lesquelles les mesures sont moyennées
+const perfSampleFrames = 60
+
+// PerfOverlay mesure le temps entre deux images et le nombre d'allocations par image, et les affiche en haut à droite
+type PerfOverlay struct {
+	frames       int
+	sampleStart  time.Time
+	mallocsStart uint64
+	frameTime    time.Duration
+	allocs       uint64
+	heap         uint64
+}
+
+// Draw met à jour les mesures puis les dessine, à appeler à la fin de Game.Draw
+func (p *PerfOverlay) Draw(screen *ebiten.Image) {
+	p.frames++
+	if p.frames >= perfSampleFrames || p.sampleStart.IsZero() {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		if !p.sampleStart.IsZero() {
+			p.frameTime = time.Since(p.sampleStart) / time.Duration(p.frames)
+			p.allocs = (stats.Mallocs - p.mallocsStart) / uint64(p.frames)
+		}
+		p.heap = stats.HeapAlloc
+		p.sampleStart = time.Now()
+		p.mallocsStart = stats.Mallocs
+		p.frames = 0
+	}
+
+	msg := fmt.Sprintf("FPS: %.0f  TPS: %.0f\nImage: %.2f ms\nAllocations / image: %d\nTas: %.1f Mo",
+		ebiten.ActualFPS(), ebiten.ActualTPS(), float64(p.frameTime.Microseconds())/1000, p.allocs, float64(p.heap)/(1<<20))
+	ebitenutil.DebugPrintAt(screen, msg, screen.Bounds().Dx()-200, 10)
+}
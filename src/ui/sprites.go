@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
+	"golang.org/x/image/math/f64"
+
+	"snake-go/src/theme"
+)
+
+type spriteKey struct {
+	theme *theme.Theme
+	name  string
+}
+
+// sous-images des planches de sprites, découpées une seule fois plutôt qu'à chaque image
+var sprites = map[spriteKey]*ebiten.Image{}
+
+// Sprite retourne l'image d'un élément de la planche de sprites d'un thème
+//
+// t: le thème
+// name: le nom de l'élément (head_up, body_h, apple...)
+// Retourne la sous-image correspondante, ou nil si l'élément n'existe pas dans le thème
+// Les éléments utilisés par la grille sont toujours présents, ils sont vérifiés au chargement
+func Sprite(t *theme.Theme, name string) *ebiten.Image {
+	key := spriteKey{theme: t, name: name}
+	if sprite, ok := sprites[key]; ok {
+		return sprite
+	}
+	rect, ok := t.Sprites[name]
+	if !ok {
+		return nil
+	}
+	sprite := Image(t.Sheet).SubImage(rect.Image()).(*ebiten.Image)
+	sprites[key] = sprite
+	return sprite
+}
+
+// Background retourne l'image de fond d'un thème, nil s'il n'en a pas
+func Background(t *theme.Theme) *ebiten.Image {
+	return Image(t.BackgroundImage)
+}
+
+// DrawFrame dessine une image d'animation d'un thème dans une case de l'écran
+//
+// screen: l'image sur laquelle dessiner
+// t: le thème dont la planche contient le sprite
+// f: l'image d'animation, obtenue avec Frame
+// x, y: le coin supérieur gauche de la case à l'écran
+// cellSize: le côté de la case à l'écran, en pixels
+// tint: la couleur appliquée au sprite, aucune si nil
+func DrawFrame(screen *ebiten.Image, t *theme.Theme, f theme.Frame, x, y, cellSize float64, tint color.Color) {
+	sprite := Sprite(t, f.Sprite)
+	if sprite == nil {
+		return
+	}
+	opts := &ebiten.DrawImageOptions{GeoM: geoM(t.Transform(f, x, y, cellSize))}
+	if tint != nil {
+		opts.ColorScale.ScaleWithColor(tint)
+	}
+	opts.ColorScale.ScaleAlpha(float32(f.Opacity))
+	screen.DrawImage(sprite, opts)
+}
+
+// DrawOutline dessine le contour d'une image d'animation : sa silhouette d'une seule couleur,
+// décalée dans les huit directions, à recouvrir ensuite par l'image elle-même
+//
+// screen, t, f, x, y, cellSize: comme pour DrawFrame
+// width: l'épaisseur du contour à l'écran, en pixels
+// c: la couleur du contour
+func DrawOutline(screen *ebiten.Image, t *theme.Theme, f theme.Frame, x, y, cellSize, width float64, c color.Color) {
+	sprite := Sprite(t, f.Sprite)
+	if sprite == nil {
+		return
+	}
+	var silhouette colorm.ColorM
+	r, g, b, _ := c.RGBA()
+	silhouette.Scale(0, 0, 0, f.Opacity)
+	silhouette.Translate(float64(r)/0xffff, float64(g)/0xffff, float64(b)/0xffff, 0)
+	for _, d := range [][2]float64{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}} {
+		opts := &colorm.DrawImageOptions{GeoM: geoM(t.Transform(f, x+d[0]*width, y+d[1]*width, cellSize))}
+		colorm.DrawImage(screen, sprite, silhouette, opts)
+	}
+}
+
+// conversion d'une transformation affine en matrice d'ebiten
+func geoM(m f64.Aff3) ebiten.GeoM {
+	var g ebiten.GeoM
+	g.SetElement(0, 0, m[0])
+	g.SetElement(0, 1, m[1])
+	g.SetElement(0, 2, m[2])
+	g.SetElement(1, 0, m[3])
+	g.SetElement(1, 1, m[4])
+	g.SetElement(1, 2, m[5])
+	return g
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+
+	"snake-go/src/theme"
+)
+
+// Durées d'une notification, en ticks
+const (
+	toastDuration = 180 // affichage complet, transitions comprises
+	toastFade     = 20  // apparition et disparition
+	toastPadding  = 12  // en pixels, à la taille normale de l'interface
+	toastMargin   = 16
+)
+
+// Toast est une notification affichée quelques secondes en haut à droite de l'écran
+type Toast struct {
+	Title string
+	Text  string
+	age   int
+}
+
+// Toasts est la file des notifications, affichées l'une après l'autre
+type Toasts struct {
+	Still bool // les notifications apparaissent en fondu sans glisser, pour réduire les mouvements
+	queue []Toast
+}
+
+// Push ajoute une notification à la file
+func (t *Toasts) Push(title, text string) {
+	t.queue = append(t.queue, Toast{Title: title, Text: text})
+}
+
+// Update fait avancer la notification affichée, à appeler à chaque tick
+func (t *Toasts) Update() {
+	if len(t.queue) == 0 {
+		return
+	}
+	t.queue[0].age++
+	if t.queue[0].age >= toastDuration {
+		t.queue = t.queue[1:]
+	}
+}
+
+// Draw dessine la notification affichée, qui descend du haut de l'écran en apparaissant
+func (t *Toasts) Draw(screen *ebiten.Image) {
+	if len(t.queue) == 0 {
+		return
+	}
+	toast := t.queue[0]
+	alpha := min(float64(toast.age), float64(toastDuration-toast.age), toastFade) / toastFade
+
+	palette := theme.Current().Palette
+	titleFace, textFace := Font(20), Font(16)
+	titleBounds, textBounds := text.BoundString(titleFace, toast.Title), text.BoundString(textFace, toast.Text)
+	titleHeight, textHeight := titleFace.Metrics().Height.Ceil(), textFace.Metrics().Height.Ceil()
+	padding, margin := Scaled(toastPadding), Scaled(toastMargin)
+	width := max(titleBounds.Dx(), textBounds.Dx()) + 2*padding
+	height := titleHeight + textHeight + 2*padding
+
+	x := screen.Bounds().Dx() - width - margin
+	y := margin
+	if !t.Still {
+		y -= int(float64(height+margin) * (1 - alpha))
+	}
+
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(float64(x), float64(y))
+	opts.ColorScale.ScaleAlpha(float32(alpha))
+	screen.DrawImage(Panel(width, height, palette.Panel), opts)
+
+	baseline := y + padding + titleFace.Metrics().Ascent.Ceil()
+	text.Draw(screen, toast.Title, titleFace, x+padding, baseline, fade(palette.Accent, alpha))
+	baseline += titleHeight
+	text.Draw(screen, toast.Text, textFace, x+padding, baseline, fade(palette.PanelText, alpha))
+}
+
+// applique une transparence à une couleur
+func fade(c color.Color, alpha float64) color.Color {
+	r, g, b, a := c.RGBA()
+	return color.RGBA64{uint16(float64(r) * alpha), uint16(float64(g) * alpha), uint16(float64(b) * alpha), uint16(float64(a) * alpha)}
+}
@@ -2,232 +2,211 @@ package ui
 
 import (
 	"fmt"
-	"image/color"
-	"io/ioutil"
-	"log"
+	"image"
+	"time"
 
 	"snake-go/src/constants"
-	"snake-go/src/resources"
+	"snake-go/src/i18n"
+	"snake-go/src/theme"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
-	"golang.org/x/image/font/opentype"
 )
 
-func loadFont(size float64) font.Face {
-    fontBytes, err := ioutil.ReadFile("assets/upheavtt.ttf")
-    if err != nil {
-        log.Fatalf("failed to read font file: %v", err)
-    }
-
-    tt, err := opentype.Parse(fontBytes)
-    if err != nil {
-        log.Fatalf("failed to parse font: %v", err)
-    }
-
-    const dpi = 72
-    face, err := opentype.NewFace(tt, &opentype.FaceOptions{
-        Size:    size,
-        DPI:     dpi,
-        Hinting: font.HintingFull,
-    })
-    if err != nil {
-        log.Fatalf("failed to create font face: %v", err)
-    }
-
-    return face
-}
-
-
-// Dessine le menu principal
-func RenderMenu(screen *ebiten.Image) {
-	textColor := color.RGBA{0, 0, 0, 255}
-	fontFace := basicfont.Face7x13
+// Dimensions de l'écran de fin de partie à la taille normale de l'interface, en pixels
+const (
+	panelSize      = 600 // côté du panneau des scores
+	gameOverMargin = 40  // marge minimale au-dessus et en dessous du panneau
+	detailsMin     = 120 // largeur en dessous de laquelle les détails de la partie ne sont pas affichés
+)
 
-	text.Draw(screen, "Menu Principal", fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2-100, textColor)
-	text.Draw(screen, "1. Commencer le jeu", fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2-50, textColor)
-	text.Draw(screen, "2. Credits", fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2, textColor)
-	text.Draw(screen, "3. Quitter", fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2+50, textColor)
+// disposition de l'écran de fin de partie
+type gameOverLayout struct {
+	panel image.Rectangle // le panneau des scores, carré et centré
+	k     float64         // agrandissement du contenu du panneau par rapport à panelSize
 }
 
-// Dessine l'écran de saisie du nom du joueur
-//
-// playerName: le nom actuellement saisi par le joueur
-func RenderNameInput(screen *ebiten.Image, playerName string) {
-	textColor := color.RGBA{0, 0, 0, 255}
-	fontFace := basicfont.Face7x13
-
-	msg := "Veuillez entrer votre nom: " + playerName
-	text.Draw(screen, msg, fontFace, constants.ScreenWidth/2-100, constants.ScreenHeight/2, textColor)
+// Dispose l'écran de fin de partie selon la taille de l'écran : le panneau suit la taille de l'interface
+// dans la limite de la hauteur de l'écran, et laisse à sa droite de la place pour les détails de la partie
+func newGameOverLayout(screenWidth, screenHeight int) gameOverLayout {
+	size := max(min(Scaled(panelSize), screenHeight-2*gameOverMargin, screenWidth*3/5), 1)
+	x := (screenWidth - size) / 2
+	y := (screenHeight - size) / 2
+	return gameOverLayout{panel: image.Rect(x, y, x+size, y+size), k: float64(size) / panelSize}
 }
 
-// Dessine l'écran de sélection du mode de jeu
-func RenderModeSelection(screen *ebiten.Image) {
-	textColor := color.RGBA{0, 0, 0, 255}
-	fontFace := basicfont.Face7x13
+// longueur du panneau de taille normale, agrandie comme le panneau
+func (l gameOverLayout) px(n int) int {
+	return int(float64(n) * l.k)
+}
 
-	text.Draw(screen, "Choisissez le mode de jeu", fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2-50, textColor)
-	text.Draw(screen, "1. Mode Classique", fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2, textColor)
-	text.Draw(screen, "2. Mode Challenge", fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2+50, textColor)
+// police agrandie comme le panneau
+func (l gameOverLayout) font(size float64) font.Face {
+	return fontFace(size * l.k)
 }
 
-// Dessine l'écran de sélection de la difficulté
+// GameOverScale retourne l'agrandissement de l'écran de fin de partie, pour que ses boutons suivent le panneau
 //
-// currentSelection: la difficulté actuellement sélectionnée
-// scores: la liste des meilleurs scores à afficher
-func RenderDifficultySelection(screen *ebiten.Image, currentSelection int, scores []Score) {
-	textColor := color.RGBA{0, 0, 0, 255}
-	fontFace := basicfont.Face7x13
-
-	text.Draw(screen, "Choix de la Difficulte", fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2-100, textColor)
-	text.Draw(screen, "Facile", fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2-50, textColor)
-	text.Draw(screen, "Normal", fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2, textColor)
-	text.Draw(screen, "Difficile", fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2+50, textColor)
-	switch currentSelection {
-	case 0:
-		text.Draw(screen, ">", fontFace, constants.ScreenWidth/2-70, constants.ScreenHeight/2-50, textColor)
-	case 1:
-		text.Draw(screen, ">", fontFace, constants.ScreenWidth/2-70, constants.ScreenHeight/2, textColor)
-	case 2:
-		text.Draw(screen, ">", fontFace, constants.ScreenWidth/2-70, constants.ScreenHeight/2+50, textColor)
-	}
-
-	text.Draw(screen, "Meilleurs scores", fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2+100, textColor)
-	for i, score := range scores {
-		text.Draw(screen, fmt.Sprintf("%d. %s: %d", i+1, score.Name, score.Value), fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2+120+(i*20), textColor)
-	}
+// screenWidth, screenHeight: la taille de l'écran logique
+func GameOverScale(screenWidth, screenHeight int) float64 {
+	return newGameOverLayout(screenWidth, screenHeight).k
 }
 
-// Dessine l'écran de fin de partie avec le score final et les meilleurs scores
+// Dessine l'écran de fin de partie avec le score final, les détails de la mort et les meilleurs scores
 //
-// score: le score final
+// summary: le score final et les détails de la partie
 // scores: la liste des meilleurs scores
-func RenderGameOver(screen *ebiten.Image, score int, scores []Score) {
-	gridX := (constants.ScreenWidth - constants.GridWidth) / 2
-	gridY := (constants.ScreenHeight - constants.GridHeight) / 2
-
-	borderColor := color.RGBA{223, 173, 59, 255}
-	borderImage := ebiten.NewImage(constants.GridWidth+2*constants.BorderThickness, constants.GridHeight+2*constants.BorderThickness)
-	borderImage.Fill(borderColor)
+func RenderGameOver(screen *ebiten.Image, summary GameOverSummary, scores []Score) {
+	layout := newGameOverLayout(screen.Bounds().Dx(), screen.Bounds().Dy())
+	panel := layout.panel
+	size := panel.Dx()
+	score := summary.Score
+
+	borderColor := theme.Current().Palette.Accent
+	borderImage := Panel(size+2*constants.BorderThickness, size+2*constants.BorderThickness, borderColor)
 	borderOpts := &ebiten.DrawImageOptions{}
-	borderOpts.GeoM.Translate(float64(gridX-constants.BorderThickness), float64(gridY-constants.BorderThickness))
+	borderOpts.GeoM.Translate(float64(panel.Min.X-constants.BorderThickness), float64(panel.Min.Y-constants.BorderThickness))
 	screen.DrawImage(borderImage, borderOpts)
 
-	backgroundColor := color.RGBA{R: 26, G: 26, B: 26, A: 255}
-	gameArea := ebiten.NewImage(constants.GridWidth, constants.GridHeight)
-	gameArea.Fill(backgroundColor)
+	backgroundColor := theme.Current().Palette.Panel
+	gameArea := Panel(size, size, backgroundColor)
 	gameAreaOpts := &ebiten.DrawImageOptions{}
-	gameAreaOpts.GeoM.Translate(float64(gridX), float64(gridY))
+	gameAreaOpts.GeoM.Translate(float64(panel.Min.X), float64(panel.Min.Y))
 	screen.DrawImage(gameArea, gameAreaOpts)
 
-	// TITLE TEXT 
-
-    titleText := "Game Over !"
-    // Obtenez les dimensions du texte 
-	titleColor := color.RGBA{223, 173, 59, 255}
-    fontTitle := loadFont(70) // Charger la police avec une taille spécifique
-    bounds := text.BoundString(fontTitle, titleText)
-    textWidth := bounds.Dx()
-    textHeight := bounds.Dy()
+	// TITLE TEXT
 
-    // Calculer les positions pour centrer le texte
-    x := gridX + (constants.GridWidth-textWidth)/2
-    y := gridY + 20
+	titleText := i18n.T("gameover.title")
+	// Obtenez les dimensions du texte
+	titleColor := theme.Current().Palette.Accent
+	fontTitle := layout.font(70) // Charger la police avec une taille spécifique
+	bounds := text.BoundString(fontTitle, titleText)
+	textWidth := bounds.Dx()
+	textHeight := bounds.Dy()
 
-    // Dessiner le texte centré
-    text.Draw(screen, titleText, fontTitle, x, y+textHeight, titleColor)
+	// Calculer les positions pour centrer le texte
+	x := panel.Min.X + (size-textWidth)/2
+	y := panel.Min.Y + layout.px(20)
 
+	// Dessiner le texte centré
+	text.Draw(screen, titleText, fontTitle, x, y+textHeight, titleColor)
 
 	// SCORE TEXT
 
-	scoreText := fmt.Sprintf("Score: %d", score)
-	
+	scoreText := i18n.T("gameover.score", score)
+
 	// Obtenez les dimensions du texte
-	scoreColor := color.RGBA{255, 255, 255, 255}
-	fontScore := loadFont(30) // Charger la police avec une taille spécifique
+	scoreColor := theme.Current().Palette.PanelText
+	fontScore := layout.font(30) // Charger la police avec une taille spécifique
 	bounds = text.BoundString(fontScore, scoreText)
 	textWidth = bounds.Dx()
 	textHeight = bounds.Dy()
 
 	// Calculer les positions pour centrer le texte
-	x = gridX + (constants.GridWidth-textWidth)/2
-	y = gridY + 100
+	x = panel.Min.X + (size-textWidth)/2
+	y = panel.Min.Y + layout.px(100)
 
 	text.Draw(screen, scoreText, fontScore, x, y+textHeight, scoreColor)
 
-    // COLUMN TITLES
-    columnTitleFont := loadFont(25)
-    playerTitle := "JOUEUR"
-    scoreTitle := "SCORE"
-    playerColumnWidth := constants.GridWidth * 0.5
-    scoreColumnWidth := constants.GridWidth * 0.2
-    columnGap := (constants.GridWidth - int(playerColumnWidth) - int(scoreColumnWidth)) / 2
-    playerTitleX := gridX + columnGap
-    scoreTitleX := playerTitleX + int(playerColumnWidth) 
-
-    text.Draw(screen, playerTitle, columnTitleFont, playerTitleX, gridY+160, color.RGBA{173, 216, 230, 255})
-    text.Draw(screen, scoreTitle, columnTitleFont, scoreTitleX, gridY+160, color.RGBA{173, 216, 230, 255})
-
-    // SCORE LIST TEXT
-    playerFont := loadFont(20)
-    scoreFont := loadFont(20)
-    column1X := playerTitleX
-    column2X := scoreTitleX
-    columnY := gridY + 200
-
-    for i, score := range scores {
-        text.Draw(screen, fmt.Sprintf("%s", score.Name), playerFont, column1X, columnY+(i*30), color.RGBA{255, 255, 204, 255})
-        text.Draw(screen, fmt.Sprintf("%d", score.Value), scoreFont, column2X, columnY+(i*30), color.RGBA{255, 255, 204, 255})
-    }
-
-    // RELAUNCH TEXT
-    relaunchText1 := "RECOMMENCER"
-    relaunchText2 := "MENU"
-    relaunchFont := loadFont(20) // Charger la police avec une taille spécifique
-
-    // Obtenez les dimensions du texte
-    bounds1 := text.BoundString(relaunchFont, relaunchText1)
-
-    textHeight1 := bounds1.Dy()
-    bounds2 := text.BoundString(relaunchFont, relaunchText2)
-
-    textHeight2 := bounds2.Dy()
-
-    // Obtenez les dimensions des images
-    rKeyImageWidth, rKeyImageHeight := resources.RKeyImage.Size()
-	enterKeyImageWidth, _ := resources.EnterKeyImage.Size()
-
-    // Positionner les images et les textes en bas à gauche de la grille
-    x1 := gridX + 20
-    y1 := gridY + constants.GridHeight - rKeyImageHeight - textHeight1 - 50
-    x2 := gridX + 20
-    y2 := y1 + rKeyImageHeight + textHeight1 + 10
-
-    // Dessiner les images et les textes
-    op := &ebiten.DrawImageOptions{}
-    op.GeoM.Translate(float64(x1), float64(y1))
-    screen.DrawImage(resources.RKeyImage, op)
-    text.Draw(screen, relaunchText1, relaunchFont, x1+rKeyImageWidth+10, y1+textHeight1+5, color.RGBA{255, 255, 255, 255})
-
-    op = &ebiten.DrawImageOptions{}
-    op.GeoM.Translate(float64(x2), float64(y2))
-    screen.DrawImage(resources.EnterKeyImage, op)
-    text.Draw(screen, relaunchText2, relaunchFont, x2+enterKeyImageWidth+10, y2+textHeight2+5, color.RGBA{255, 255, 255, 255})
+	// COLUMN TITLES
+	columnTitleFont := layout.font(25)
+	playerTitle := i18n.T("gameover.player_column")
+	scoreTitle := i18n.T("gameover.score_column")
+	playerColumnWidth := size / 2
+	scoreColumnWidth := size / 5
+	columnGap := (size - playerColumnWidth - scoreColumnWidth) / 2
+	playerTitleX := panel.Min.X + columnGap
+	scoreTitleX := playerTitleX + playerColumnWidth
+
+	text.Draw(screen, playerTitle, columnTitleFont, playerTitleX, panel.Min.Y+layout.px(160), theme.Current().Palette.Highlight)
+	text.Draw(screen, scoreTitle, columnTitleFont, scoreTitleX, panel.Min.Y+layout.px(160), theme.Current().Palette.Highlight)
+
+	// SCORE LIST TEXT
+	playerFont := layout.font(20)
+	scoreFont := layout.font(20)
+	column1X := playerTitleX
+	column2X := scoreTitleX
+	columnY := panel.Min.Y + layout.px(200)
+
+	for i, score := range scores {
+		text.Draw(screen, fmt.Sprintf("%s", score.Name), playerFont, column1X, columnY+i*layout.px(30), theme.Current().Palette.PanelText)
+		text.Draw(screen, fmt.Sprintf("%d", score.Value), scoreFont, column2X, columnY+i*layout.px(30), theme.Current().Palette.PanelText)
+	}
+
+	renderGameOverDetails(screen, layout, summary)
+}
+
+// GameOverButtonsOrigin retourne la position des boutons "recommencer" et "menu", en bas à gauche du panneau de fin de partie
+//
+// screenWidth, screenHeight: la taille de l'écran logique
+// buttonsHeight: la hauteur occupée par les boutons
+func GameOverButtonsOrigin(screenWidth, screenHeight, buttonsHeight int) image.Point {
+	layout := newGameOverLayout(screenWidth, screenHeight)
+	return image.Pt(layout.panel.Min.X+layout.px(40), layout.panel.Max.Y-buttonsHeight-layout.px(30))
 }
 
-// Dessine l'écran des crédits
-func RenderCredits(screen *ebiten.Image) {
-	textColor := color.RGBA{0, 0, 0, 255}
-	fontFace := basicfont.Face7x13
+// Dessine à droite du panneau l'image figée du plateau final et les statistiques de la partie,
+// rien s'il ne reste pas assez de place à droite du panneau
+//
+// layout: la disposition de l'écran de fin de partie
+// summary: les détails de la partie
+func renderGameOverDetails(screen *ebiten.Image, layout gameOverLayout, summary GameOverSummary) {
+	panelX := layout.panel.Max.X + constants.BorderThickness + layout.px(20)
+	panelWidth := screen.Bounds().Dx() - panelX - layout.px(20)
+	if panelWidth < layout.px(detailsMin) {
+		return
+	}
+
+	y := layout.panel.Min.Y
+	if summary.Board != nil {
+		// le plateau occupe au plus la moitié de la hauteur du panneau, les statistiques restent en dessous
+		boardBounds := summary.Board.Bounds()
+		scale := min(float64(panelWidth)/float64(boardBounds.Dx()), float64(layout.panel.Dy()/2)/float64(boardBounds.Dy()))
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Scale(scale, scale)
+		opts.GeoM.Translate(float64(panelX), float64(y))
+		screen.DrawImage(summary.Board, opts)
+		y += int(float64(boardBounds.Dy())*scale) + layout.px(30)
+	}
+
+	detailsFont := layout.font(20)
+	labelColor := theme.Current().Palette.Highlight
+	valueColor := theme.Current().Palette.Text
+	details := []struct {
+		label string
+		value string
+	}{
+		{i18n.T("gameover.cause"), summary.Cause},
+		{i18n.T("gameover.length"), summary.Length},
+		{i18n.T("gameover.time"), formatDuration(summary.PlayTime)},
+		{i18n.T("gameover.apples_per_minute"), fmt.Sprintf("%.1f", summary.ApplesPerMinute)},
+	}
+	for _, detail := range details {
+		text.Draw(screen, detail.label, detailsFont, panelX, y, labelColor)
+		text.Draw(screen, detail.value, basicfont.Face7x13, panelX, y+layout.px(20), valueColor)
+		y += layout.px(50)
+	}
+}
 
-	text.Draw(screen, "Credits", fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2-100, textColor)
-	text.Draw(screen, "Developpe par Florent Weltmann, Dantin Durand", fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2-50, textColor)
-	text.Draw(screen, "Appuyez sur Echap pour revenir au menu", fontFace, constants.ScreenWidth/2-50, constants.ScreenHeight/2, textColor)
+// Formate une durée en minutes et secondes (m:ss)
+func formatDuration(d time.Duration) string {
+	seconds := int(d.Round(time.Second).Seconds())
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
 }
 
 type Score struct {
 	Value int
 	Name  string
 }
+
+// Détails de la partie affichés sur l'écran de fin de partie
+type GameOverSummary struct {
+	Score           int
+	Cause           string
+	Length          string // longueur finale du serpent, déjà formatée
+	PlayTime        time.Duration
+	ApplesPerMinute float64
+	Board           *ebiten.Image
+}
@@ -0,0 +1,73 @@
+package widget
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Button est un texte cliquable qui appelle OnClick lorsqu'il est activé
+type Button struct {
+	base
+	Text      string
+	TextSize  float64
+	Shortcuts []ebiten.Key // touches qui activent le bouton même sans le focus
+	OnClick   func()
+}
+
+// NewButton crée un bouton
+func NewButton(text string, onClick func()) *Button {
+	return &Button{Text: text, OnClick: onClick}
+}
+
+// WithShortcut ajoute une touche de raccourci au bouton
+func (b *Button) WithShortcut(keys ...ebiten.Key) *Button {
+	b.Shortcuts = append(b.Shortcuts, keys...)
+	return b
+}
+
+func (b *Button) Focusable() bool {
+	return true
+}
+
+func (b *Button) Describe() string {
+	return b.Text
+}
+
+func (b *Button) Size() (int, int) {
+	return measure(face(b.TextSize), b.Text)
+}
+
+func (b *Button) Update(in *Input) {
+	if in.Activate || in.ClickedIn(b.bounds) {
+		in.Activate = false
+		in.Clicked = false
+		b.click()
+	}
+}
+
+// permet au panneau de trouver les boutons, y compris ceux qui embarquent un Button
+func (b *Button) shortcut() *Button {
+	return b
+}
+
+// indique si l'une des touches de raccourci vient d'être enfoncée
+func (b *Button) shortcutPressed() bool {
+	for _, key := range b.Shortcuts {
+		if inpututil.IsKeyJustPressed(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Button) click() {
+	if b.OnClick != nil {
+		b.OnClick()
+	}
+}
+
+func (b *Button) Draw(screen *ebiten.Image, state State) {
+	f := face(b.TextSize)
+	drawFocusMarker(screen, f, b.bounds, state)
+	drawText(screen, f, b.Text, b.bounds, b.bounds.Min.X, textColor(state))
+}
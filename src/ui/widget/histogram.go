@@ -0,0 +1,69 @@
+package widget
+
+import (
+	"image"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+
+	"snake-go/src/theme"
+	"snake-go/src/ui"
+)
+
+// Dimensions d'un histogramme à la taille normale de l'interface
+const (
+	histogramBarWidth  = 36
+	histogramBarGap    = 8
+	histogramBarHeight = 90 // hauteur de la barre la plus haute
+	histogramTextSize  = 12
+)
+
+// Histogram dessine des barres verticales avec leur valeur au-dessus et leur libellé en dessous
+type Histogram struct {
+	base
+	Values []int
+	Labels []string
+}
+
+// NewHistogram crée un histogramme, labels donne le libellé de chaque barre
+func NewHistogram(values []int, labels []string) *Histogram {
+	return &Histogram{Values: values, Labels: labels}
+}
+
+func (h *Histogram) Size() (int, int) {
+	_, textHeight := measure(face(histogramTextSize), "0")
+	width := len(h.Values)*ui.Scaled(histogramBarWidth+histogramBarGap) - ui.Scaled(histogramBarGap)
+	return max(width, 0), ui.Scaled(histogramBarHeight) + 2*(textHeight+ui.Scaled(4))
+}
+
+func (h *Histogram) Draw(screen *ebiten.Image, state State) {
+	f := face(histogramTextSize)
+	palette := theme.Current().Palette
+	_, textHeight := measure(f, "0")
+	highest := 1
+	for _, v := range h.Values {
+		highest = max(highest, v)
+	}
+
+	barWidth, barHeightMax, gap := ui.Scaled(histogramBarWidth), ui.Scaled(histogramBarHeight), ui.Scaled(4)
+	baseline := h.bounds.Min.Y + textHeight + gap + barHeightMax
+	for i, v := range h.Values {
+		x := h.bounds.Min.X + i*ui.Scaled(histogramBarWidth+histogramBarGap)
+		barHeight := v * barHeightMax / highest
+		if barHeight > 0 {
+			opts := &ebiten.DrawImageOptions{}
+			opts.GeoM.Translate(float64(x), float64(baseline-barHeight))
+			screen.DrawImage(ui.Panel(barWidth, barHeight, palette.Accent), opts)
+		}
+
+		value := strconv.Itoa(v)
+		width, _ := measure(f, value)
+		text.Draw(screen, value, f, x+(barWidth-width)/2, baseline-barHeight-gap, palette.Text)
+		if i < len(h.Labels) {
+			width, _ = measure(f, h.Labels[i])
+			labelBounds := image.Rect(x, baseline+gap, x+barWidth, baseline+gap+textHeight)
+			drawText(screen, f, h.Labels[i], labelBounds, x+(barWidth-width)/2, palette.Text)
+		}
+	}
+}
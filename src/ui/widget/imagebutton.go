@@ -0,0 +1,61 @@
+package widget
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"snake-go/src/ui"
+)
+
+// écart entre l'image et le texte d'un bouton image, à la taille normale de l'interface
+const imageButtonGap = 10
+
+// ImageButton est un bouton composé d'une image (une touche du clavier par exemple) suivie d'un texte
+type ImageButton struct {
+	Button
+	Image *ebiten.Image
+	Color color.Color // couleur du texte sans focus ni survol, celle du thème si nil
+}
+
+// NewImageButton crée un bouton image
+func NewImageButton(img *ebiten.Image, text string, onClick func()) *ImageButton {
+	return &ImageButton{Button: Button{Text: text, OnClick: onClick}, Image: img}
+}
+
+// WithShortcut ajoute une touche de raccourci au bouton
+func (b *ImageButton) WithShortcut(keys ...ebiten.Key) *ImageButton {
+	b.Button.WithShortcut(keys...)
+	return b
+}
+
+// taille de l'image agrandie à la taille de l'interface
+func (b *ImageButton) imageSize() (int, int) {
+	return ui.Scaled(b.Image.Bounds().Dx()), ui.Scaled(b.Image.Bounds().Dy())
+}
+
+func (b *ImageButton) Size() (int, int) {
+	textWidth, textHeight := b.Button.Size()
+	imageWidth, imageHeight := b.imageSize()
+	return imageWidth + ui.Scaled(imageButtonGap) + textWidth, max(imageHeight, textHeight)
+}
+
+func (b *ImageButton) Draw(screen *ebiten.Image, state State) {
+	f := face(b.TextSize)
+	drawFocusMarker(screen, f, b.bounds, state)
+
+	imageWidth, imageHeight := b.imageSize()
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Scale(ui.Scale(), ui.Scale())
+	opts.GeoM.Translate(float64(b.bounds.Min.X), float64(b.bounds.Min.Y+(b.bounds.Dy()-imageHeight)/2))
+	if !state.Focused && !state.Hovered {
+		opts.ColorScale.ScaleAlpha(0.7)
+	}
+	screen.DrawImage(b.Image, opts)
+
+	c := textColor(state)
+	if !state.Focused && !state.Hovered && b.Color != nil {
+		c = b.Color
+	}
+	drawText(screen, f, b.Text, b.bounds, b.bounds.Min.X+imageWidth+ui.Scaled(imageButtonGap), c)
+}
@@ -0,0 +1,84 @@
+package widget
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Délais de répétition des touches maintenues, en ticks
+const (
+	repeatDelay    = 24
+	repeatInterval = 4
+)
+
+// Input regroupe les entrées d'un tick, déjà traduites en actions d'interface
+// Un widget qui utilise une action la remet à false pour que son panneau ne la traite pas une seconde fois
+type Input struct {
+	CursorX, CursorY int
+	MouseMoved       bool
+	Clicked          bool // bouton gauche de la souris enfoncé pendant ce tick
+	MouseDown        bool // bouton gauche de la souris maintenu
+
+	Up, Down, Left, Right bool
+	Next, Previous        bool // Tab et Maj+Tab
+	Activate              bool // Entrée, Espace ou bouton A de la manette
+	Back                  bool // Echap ou bouton B de la manette
+}
+
+// dernière position connue du curseur, pour détecter les mouvements de la souris
+var lastCursor image.Point
+
+// ReadInput lit l'état du clavier, de la souris et des manettes pour ce tick
+func ReadInput() *Input {
+	in := &Input{}
+
+	// ebiten convertit la position du curseur dans les coordonnées de l'écran logique,
+	// le test de survol reste donc juste quand la fenêtre est redimensionnée ou réduite par Layout
+	in.CursorX, in.CursorY = ebiten.CursorPosition()
+	cursor := image.Pt(in.CursorX, in.CursorY)
+	in.MouseMoved = cursor != lastCursor
+	lastCursor = cursor
+	in.Clicked = inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	in.MouseDown = ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	in.Up = KeyRepeated(ebiten.KeyArrowUp)
+	in.Down = KeyRepeated(ebiten.KeyArrowDown)
+	in.Left = KeyRepeated(ebiten.KeyArrowLeft)
+	in.Right = KeyRepeated(ebiten.KeyArrowRight)
+	in.Next = inpututil.IsKeyJustPressed(ebiten.KeyTab) && !shift
+	in.Previous = inpututil.IsKeyJustPressed(ebiten.KeyTab) && shift
+	in.Activate = inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace)
+	in.Back = inpututil.IsKeyJustPressed(ebiten.KeyEscape)
+
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		in.Up = in.Up || inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButtonLeftTop)
+		in.Down = in.Down || inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButtonLeftBottom)
+		in.Left = in.Left || inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButtonLeftLeft)
+		in.Right = in.Right || inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButtonLeftRight)
+		in.Activate = in.Activate || inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButtonRightBottom)
+		in.Back = in.Back || inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButtonRightRight)
+	}
+	return in
+}
+
+// KeyRepeated indique si une touche vient d'être enfoncée, ou si elle est maintenue assez longtemps pour se répéter
+func KeyRepeated(key ebiten.Key) bool {
+	duration := inpututil.KeyPressDuration(key)
+	return duration == 1 || (duration >= repeatDelay && (duration-repeatDelay)%repeatInterval == 0)
+}
+
+// Hit indique si le curseur est dans le rectangle
+func (in *Input) Hit(bounds image.Rectangle) bool {
+	return image.Pt(in.CursorX, in.CursorY).In(bounds)
+}
+
+// ClickedIn indique si un clic a eu lieu dans le rectangle
+func (in *Input) ClickedIn(bounds image.Rectangle) bool {
+	return in.Clicked && in.Hit(bounds)
+}
@@ -0,0 +1,37 @@
+package widget
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Label est un texte non interactif
+type Label struct {
+	base
+	Text     string
+	TextSize float64     // 0 pour la taille par défaut
+	Color    color.Color // nil pour la couleur de texte du thème
+}
+
+// NewLabel crée un texte de taille par défaut
+func NewLabel(text string) *Label {
+	return &Label{Text: text}
+}
+
+// NewTitle crée un titre
+func NewTitle(text string) *Label {
+	return &Label{Text: text, TextSize: DefaultTitleSize}
+}
+
+func (l *Label) Size() (int, int) {
+	return measure(face(l.TextSize), l.Text)
+}
+
+func (l *Label) Draw(screen *ebiten.Image, state State) {
+	c := l.Color
+	if c == nil {
+		c = textColor(State{})
+	}
+	drawText(screen, face(l.TextSize), l.Text, l.bounds, l.bounds.Min.X, c)
+}
@@ -0,0 +1,121 @@
+package widget
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"snake-go/src/ui"
+)
+
+// List est une liste verticale d'éléments dont un seul est sélectionné
+// Haut et bas changent la sélection, le focus ne quitte la liste qu'en dépassant le premier ou le dernier élément
+type List struct {
+	base
+	Items      []string
+	Selected   int
+	TextSize   float64
+	Spacing    int
+	OnChange   func(index int) // appelée quand la sélection change
+	OnActivate func(index int) // appelée quand l'élément sélectionné est validé ou cliqué
+	hovered    int
+}
+
+// NewList crée une liste
+func NewList(items []string, onActivate func(index int)) *List {
+	return &List{Items: items, Spacing: 12, OnActivate: onActivate, hovered: -1}
+}
+
+func (l *List) Focusable() bool {
+	return true
+}
+
+func (l *List) Describe() string {
+	if l.Selected < 0 || l.Selected >= len(l.Items) {
+		return ""
+	}
+	return l.Items[l.Selected]
+}
+
+func (l *List) Size() (int, int) {
+	f := face(l.TextSize)
+	width, height := 0, 0
+	for i, item := range l.Items {
+		w, h := measure(f, item)
+		width = max(width, w)
+		height += h
+		if i > 0 {
+			height += ui.Scaled(l.Spacing)
+		}
+	}
+	return width, height
+}
+
+// rectangle de l'élément i
+func (l *List) itemBounds(i int) image.Rectangle {
+	_, lineHeight := measure(face(l.TextSize), "")
+	y := l.bounds.Min.Y + i*(lineHeight+ui.Scaled(l.Spacing))
+	return image.Rect(l.bounds.Min.X, y, l.bounds.Max.X, y+lineHeight)
+}
+
+// indice de l'élément sous le curseur, -1 s'il n'y en a pas
+func (l *List) itemAt(in *Input) int {
+	for i := range l.Items {
+		if in.Hit(l.itemBounds(i)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (l *List) Update(in *Input) {
+	l.hovered = l.itemAt(in)
+	if in.MouseMoved && l.hovered >= 0 {
+		l.selectItem(l.hovered)
+	}
+
+	switch {
+	case in.Up && l.Selected > 0:
+		in.Up = false
+		l.selectItem(l.Selected - 1)
+	case in.Down && l.Selected < len(l.Items)-1:
+		in.Down = false
+		l.selectItem(l.Selected + 1)
+	case in.Clicked && l.hovered >= 0:
+		in.Clicked = false
+		l.selectItem(l.hovered)
+		l.activate()
+	case in.Activate:
+		in.Activate = false
+		l.activate()
+	}
+}
+
+func (l *List) selectItem(index int) {
+	if index == l.Selected {
+		return
+	}
+	l.Selected = index
+	if l.OnChange != nil {
+		l.OnChange(index)
+	}
+}
+
+func (l *List) activate() {
+	if l.OnActivate != nil && l.Selected >= 0 && l.Selected < len(l.Items) {
+		l.OnActivate(l.Selected)
+	}
+}
+
+func (l *List) Draw(screen *ebiten.Image, state State) {
+	f := face(l.TextSize)
+	for i, item := range l.Items {
+		itemState := State{Focused: state.Focused && i == l.Selected, Hovered: i == l.hovered}
+		if !state.Focused && i == l.Selected {
+			itemState.Hovered = true // la sélection reste visible quand la liste n'a pas le focus
+		}
+		bounds := l.itemBounds(i)
+		drawFocusMarker(screen, f, bounds, itemState)
+		drawText(screen, f, item, bounds, bounds.Min.X, textColor(itemState))
+	}
+}
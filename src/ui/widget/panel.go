@@ -0,0 +1,165 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"snake-go/src/ui"
+)
+
+// Panel dispose ses widgets en colonne, centrée dans la zone qui lui est donnée, et gère le focus entre eux
+// Haut, bas et Tab déplacent le focus, le survol de la souris le donne au widget pointé
+type Panel struct {
+	base
+	Children   []Widget
+	Spacing    int // écarts en pixels à la taille normale, agrandis à la taille de l'interface
+	Padding    int
+	Background color.Color // nil pour un panneau transparent
+	OnBack     func()      // appelée avec Echap ou le bouton B de la manette
+	// Position place le panneau dans la zone donnée à Layout, le panneau est centré si nil
+	Position func(area image.Rectangle, width, height int) image.Point
+	focus    int
+	hovered  int
+}
+
+// NewPanel crée un panneau contenant les widgets donnés, le focus est donné au premier widget qui l'accepte
+func NewPanel(children ...Widget) *Panel {
+	p := &Panel{Children: children, Spacing: 24, Padding: 24, focus: -1, hovered: -1}
+	p.focus = p.nextFocusable(-1, 1)
+	return p
+}
+
+// Add ajoute des widgets à la fin du panneau
+func (p *Panel) Add(children ...Widget) {
+	p.Children = append(p.Children, children...)
+	if p.focus < 0 {
+		p.focus = p.nextFocusable(-1, 1)
+	}
+}
+
+// Focus donne le focus au widget, s'il fait partie du panneau et l'accepte
+func (p *Panel) Focus(w Widget) {
+	for i, child := range p.Children {
+		if child == w && isFocusable(child) {
+			p.focus = i
+		}
+	}
+}
+
+// Focused retourne le widget qui a le focus, ou nil
+func (p *Panel) Focused() Widget {
+	if p.focus < 0 || p.focus >= len(p.Children) {
+		return nil
+	}
+	return p.Children[p.focus]
+}
+
+// Describe retourne la description du widget qui a le focus
+func (p *Panel) Describe() string {
+	return Describe(p.Focused())
+}
+
+func isFocusable(w Widget) bool {
+	f, ok := w.(Focusable)
+	return ok && f.Focusable()
+}
+
+// indice du prochain widget qui accepte le focus dans la direction donnée, en bouclant
+func (p *Panel) nextFocusable(from, direction int) int {
+	n := len(p.Children)
+	for step := 1; step <= n; step++ {
+		i := ((from+direction*step)%n + n) % n
+		if isFocusable(p.Children[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *Panel) Size() (int, int) {
+	spacing, padding := ui.Scaled(p.Spacing), ui.Scaled(p.Padding)
+	width, height := 0, 0
+	for i, child := range p.Children {
+		w, h := child.Size()
+		width = max(width, w)
+		height += h
+		if i > 0 {
+			height += spacing
+		}
+	}
+	return width + 2*padding, height + 2*padding
+}
+
+// Layout place le panneau dans la zone donnée, centré sauf si Position est défini, puis place ses widgets en colonne
+// Les widgets sont alignés à gauche dans la colonne
+//
+// area: la zone disponible, en général l'écran entier
+func (p *Panel) Layout(area image.Rectangle) {
+	width, height := p.Size()
+	x := area.Min.X + (area.Dx()-width)/2
+	y := area.Min.Y + (area.Dy()-height)/2
+	if p.Position != nil {
+		origin := p.Position(area, width, height)
+		x, y = origin.X, origin.Y
+	}
+	p.SetBounds(image.Rect(x, y, x+width, y+height))
+
+	spacing, padding := ui.Scaled(p.Spacing), ui.Scaled(p.Padding)
+	y += padding
+	for _, child := range p.Children {
+		w, h := child.Size()
+		child.SetBounds(image.Rect(x+padding, y, x+padding+w, y+h))
+		y += h + spacing
+	}
+}
+
+// Update traite les entrées du tick : raccourcis, souris, widget qui a le focus puis navigation
+func (p *Panel) Update(in *Input) {
+	for _, child := range p.Children {
+		if button, ok := child.(interface{ shortcut() *Button }); ok && button.shortcut().shortcutPressed() {
+			button.shortcut().click()
+			return
+		}
+	}
+
+	p.hovered = -1
+	for i, child := range p.Children {
+		if isFocusable(child) && in.Hit(child.Bounds()) {
+			p.hovered = i
+		}
+	}
+	if p.hovered >= 0 && (in.MouseMoved || in.Clicked) {
+		p.focus = p.hovered
+	}
+	if p.hovered >= 0 {
+		ebiten.SetCursorShape(ebiten.CursorShapePointer)
+	} else {
+		ebiten.SetCursorShape(ebiten.CursorShapeDefault)
+	}
+
+	if focused := p.Focused(); focused != nil {
+		focused.Update(in)
+	}
+
+	switch {
+	case in.Up || in.Previous:
+		p.focus = p.nextFocusable(p.focus, -1)
+	case in.Down || in.Next:
+		p.focus = p.nextFocusable(p.focus, 1)
+	case in.Back && p.OnBack != nil:
+		p.OnBack()
+	}
+}
+
+func (p *Panel) Draw(screen *ebiten.Image, state State) {
+	if p.Background != nil {
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Translate(float64(p.bounds.Min.X), float64(p.bounds.Min.Y))
+		screen.DrawImage(ui.Panel(p.bounds.Dx(), p.bounds.Dy(), p.Background), opts)
+	}
+	for i, child := range p.Children {
+		child.Draw(screen, State{Focused: i == p.focus, Hovered: i == p.hovered})
+	}
+}
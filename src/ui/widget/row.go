@@ -0,0 +1,109 @@
+package widget
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"snake-go/src/ui"
+)
+
+// Row dispose des widgets sur une ligne, gauche et droite déplacent le focus entre eux
+// Le panneau voit la ligne comme un seul widget, Row transmet les entrées au widget qui a le focus
+type Row struct {
+	base
+	Children []Widget
+	Spacing  int
+	focus    int
+	hovered  int
+}
+
+// NewRow crée une ligne contenant les widgets donnés, le focus est donné au premier widget qui l'accepte
+func NewRow(children ...Widget) *Row {
+	r := &Row{Children: children, Spacing: 24, hovered: -1}
+	r.focus = r.nextFocusable(-1, 1)
+	return r
+}
+
+// indice du prochain widget qui accepte le focus dans la direction donnée, sans boucler
+func (r *Row) nextFocusable(from, direction int) int {
+	for i := from + direction; i >= 0 && i < len(r.Children); i += direction {
+		if isFocusable(r.Children[i]) {
+			return i
+		}
+	}
+	return from
+}
+
+func (r *Row) Focusable() bool {
+	for _, child := range r.Children {
+		if isFocusable(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// Describe retourne la description du widget de la ligne qui a le focus
+func (r *Row) Describe() string {
+	if r.focus < 0 || r.focus >= len(r.Children) {
+		return ""
+	}
+	return Describe(r.Children[r.focus])
+}
+
+func (r *Row) Size() (int, int) {
+	width, height := 0, 0
+	for i, child := range r.Children {
+		w, h := child.Size()
+		width += w
+		height = max(height, h)
+		if i > 0 {
+			width += ui.Scaled(r.Spacing)
+		}
+	}
+	return width, height
+}
+
+// SetBounds place la ligne puis ses widgets de gauche à droite, centrés verticalement
+func (r *Row) SetBounds(bounds image.Rectangle) {
+	r.base.SetBounds(bounds)
+	x := bounds.Min.X
+	for _, child := range r.Children {
+		w, h := child.Size()
+		y := bounds.Min.Y + (bounds.Dy()-h)/2
+		child.SetBounds(image.Rect(x, y, x+w, y+h))
+		x += w + ui.Scaled(r.Spacing)
+	}
+}
+
+func (r *Row) Update(in *Input) {
+	r.hovered = -1
+	for i, child := range r.Children {
+		if isFocusable(child) && in.Hit(child.Bounds()) {
+			r.hovered = i
+		}
+	}
+	if r.hovered >= 0 && (in.MouseMoved || in.Clicked) {
+		r.focus = r.hovered
+	}
+
+	if r.focus >= 0 && r.focus < len(r.Children) {
+		r.Children[r.focus].Update(in)
+	}
+
+	switch {
+	case in.Left:
+		in.Left = false
+		r.focus = r.nextFocusable(r.focus, -1)
+	case in.Right:
+		in.Right = false
+		r.focus = r.nextFocusable(r.focus, 1)
+	}
+}
+
+func (r *Row) Draw(screen *ebiten.Image, state State) {
+	for i, child := range r.Children {
+		child.Draw(screen, State{Focused: state.Focused && i == r.focus, Hovered: state.Hovered && i == r.hovered})
+	}
+}
@@ -0,0 +1,142 @@
+package widget
+
+import (
+	"image"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"snake-go/src/theme"
+	"snake-go/src/ui"
+)
+
+// Dimensions de la piste d'un curseur à la taille normale de l'interface
+const (
+	sliderTrackWidth  = 200
+	sliderTrackHeight = 6
+	sliderKnobWidth   = 10
+	sliderGap         = 24
+)
+
+// Slider permet de choisir une valeur entière entre Min et Max, avec gauche et droite ou en cliquant sur la piste
+type Slider struct {
+	base
+	Label    string
+	Value    int
+	Min, Max int
+	Step     int
+	TextSize float64
+	Format   func(value int) string // texte affiché pour la valeur, le nombre lui-même si nil
+	OnChange func(value int)
+	dragging bool
+}
+
+// NewSlider crée un curseur
+func NewSlider(label string, value, minValue, maxValue int, onChange func(value int)) *Slider {
+	return &Slider{Label: label, Value: value, Min: minValue, Max: maxValue, Step: 1, OnChange: onChange}
+}
+
+func (s *Slider) Focusable() bool {
+	return true
+}
+
+// largeur réservée au libellé, pour que la piste soit alignée
+func (s *Slider) labelWidth() int {
+	width, _ := measure(face(s.TextSize), s.Label)
+	return width
+}
+
+func (s *Slider) valueText() string {
+	if s.Format != nil {
+		return s.Format(s.Value)
+	}
+	return strconv.Itoa(s.Value)
+}
+
+func (s *Slider) Describe() string {
+	return s.Label + " : " + s.valueText()
+}
+
+func (s *Slider) Size() (int, int) {
+	f := face(s.TextSize)
+	valueWidth := 0
+	for _, v := range []int{s.Min, s.Max, s.Value} {
+		saved := s.Value
+		s.Value = v
+		w, _ := measure(f, s.valueText())
+		s.Value = saved
+		valueWidth = max(valueWidth, w)
+	}
+	_, height := measure(f, s.Label)
+	return s.labelWidth() + ui.Scaled(sliderGap+sliderTrackWidth+sliderGap) + valueWidth, height
+}
+
+// rectangle cliquable de la piste
+func (s *Slider) trackBounds() image.Rectangle {
+	x := s.bounds.Min.X + s.labelWidth() + ui.Scaled(sliderGap)
+	return image.Rect(x, s.bounds.Min.Y, x+ui.Scaled(sliderTrackWidth), s.bounds.Max.Y)
+}
+
+func (s *Slider) Update(in *Input) {
+	step := max(s.Step, 1)
+	switch {
+	case in.Left:
+		in.Left = false
+		s.setValue(s.Value - step)
+	case in.Right:
+		in.Right = false
+		s.setValue(s.Value + step)
+	}
+
+	track := s.trackBounds()
+	if in.ClickedIn(track) {
+		in.Clicked = false
+		s.dragging = true
+	}
+	if !in.MouseDown {
+		s.dragging = false
+	}
+	if s.dragging && s.Max > s.Min {
+		ratio := float64(in.CursorX-track.Min.X) / float64(track.Dx())
+		ratio = min(max(ratio, 0), 1)
+		value := s.Min + int(ratio*float64(s.Max-s.Min)+0.5)
+		s.setValue(s.Min + (value-s.Min)/step*step)
+	}
+}
+
+func (s *Slider) setValue(value int) {
+	value = min(max(value, s.Min), s.Max)
+	if value == s.Value {
+		return
+	}
+	s.Value = value
+	if s.OnChange != nil {
+		s.OnChange(value)
+	}
+}
+
+func (s *Slider) Draw(screen *ebiten.Image, state State) {
+	f := face(s.TextSize)
+	c := textColor(state)
+	drawFocusMarker(screen, f, s.bounds, state)
+	drawText(screen, f, s.Label, s.bounds, s.bounds.Min.X, c)
+
+	track := s.trackBounds()
+	palette := theme.Current().Palette
+	trackHeight, knobWidth := ui.Scaled(sliderTrackHeight), ui.Scaled(sliderKnobWidth)
+	trackY := track.Min.Y + (track.Dy()-trackHeight)/2
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(float64(track.Min.X), float64(trackY))
+	screen.DrawImage(ui.Panel(track.Dx(), trackHeight, palette.Border), opts)
+
+	ratio := 0.0
+	if s.Max > s.Min {
+		ratio = float64(s.Value-s.Min) / float64(s.Max-s.Min)
+	}
+	knobX := track.Min.X + int(ratio*float64(track.Dx()-knobWidth))
+	opts = &ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(float64(knobX), float64(track.Min.Y))
+	screen.DrawImage(ui.Panel(knobWidth, track.Dy(), c), opts)
+
+	drawText(screen, f, s.valueText(), s.bounds, track.Max.X+ui.Scaled(sliderGap), c)
+}
@@ -0,0 +1,217 @@
+package widget
+
+import (
+	"image"
+	"log"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"golang.org/x/image/font"
+
+	"snake-go/src/clipboard"
+	"snake-go/src/i18n"
+	"snake-go/src/theme"
+	"snake-go/src/ui"
+)
+
+// Dimensions du champ de saisie à la taille normale de l'interface
+const (
+	textFieldPadding  = 8
+	textFieldCaret    = 2
+	textFieldBlink    = 30 // durée d'une phase du clignotement du curseur, en ticks
+	textFieldMessages = 16 // taille du texte des messages de validation
+)
+
+// TextField est un champ de saisie d'une ligne
+// Le texte est édité caractère par caractère (et non octet par octet) : gauche, droite, Origine et Fin déplacent
+// le curseur, Retour arrière et Suppr effacent, Ctrl+V colle le presse-papiers
+type TextField struct {
+	base
+	Text      string
+	MaxLength int // nombre maximal de caractères, 0 pour ne pas limiter
+	Width     int // largeur du champ en pixels, à la taille normale de l'interface
+	TextSize  float64
+	Accept    func(r rune) bool // caractères acceptés, lettres et chiffres si nil
+	// Validate retourne le message à afficher sous le champ si le texte ne peut pas être validé, ou ""
+	Validate func(text string) string
+	OnChange func(text string)
+	OnSubmit func(text string) // appelée avec Entrée, si le texte est valide
+	Message  string            // message affiché sous le champ
+	cursor   int               // position du curseur, en caractères
+	blink    int
+}
+
+// NewTextField crée un champ de saisie, avec le curseur à la fin du texte
+func NewTextField(text string, maxLength int, onSubmit func(text string)) *TextField {
+	return &TextField{Text: text, MaxLength: maxLength, Width: 320, OnSubmit: onSubmit, cursor: utf8.RuneCountInString(text)}
+}
+
+func (t *TextField) Focusable() bool {
+	return true
+}
+
+func (t *TextField) Describe() string {
+	if t.Message != "" {
+		return t.Text + " : " + t.Message
+	}
+	return t.Text
+}
+
+// SetText remplace le texte du champ et place le curseur à la fin
+func (t *TextField) SetText(text string) {
+	t.Text = text
+	t.cursor = utf8.RuneCountInString(text)
+	t.Message = ""
+}
+
+// hauteur du champ lui-même, sans la ligne des messages
+func (t *TextField) fieldHeight() int {
+	_, height := measure(face(t.TextSize), t.Text)
+	return height + 2*ui.Scaled(textFieldPadding)
+}
+
+// la ligne des messages est toujours réservée pour que les widgets suivants ne bougent pas
+func (t *TextField) Size() (int, int) {
+	_, messageHeight := measure(face(textFieldMessages), t.Message)
+	return ui.Scaled(t.Width), t.fieldHeight() + ui.Scaled(textFieldPadding) + messageHeight
+}
+
+func (t *TextField) accept(r rune) bool {
+	if t.Accept != nil {
+		return t.Accept(r)
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// insère les caractères acceptés à la position du curseur, dans la limite de MaxLength
+// Retourne true si des caractères ont été refusés parce que le champ est plein
+func (t *TextField) insert(chars []rune) bool {
+	runes := []rune(t.Text)
+	for _, r := range chars {
+		if !t.accept(r) {
+			continue
+		}
+		if t.MaxLength > 0 && len(runes) >= t.MaxLength {
+			t.Text = string(runes)
+			return true
+		}
+		runes = append(runes[:t.cursor], append([]rune{r}, runes[t.cursor:]...)...)
+		t.cursor++
+	}
+	t.Text = string(runes)
+	return false
+}
+
+func (t *TextField) Update(in *Input) {
+	runes := []rune(t.Text)
+	t.cursor = min(max(t.cursor, 0), len(runes))
+	previous, cursor := t.Text, t.cursor
+	control := ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta)
+	limited := t.insert(ebiten.AppendInputChars(nil))
+
+	runes = []rune(t.Text)
+	switch {
+	case KeyRepeated(ebiten.KeyBackspace) && t.cursor > 0:
+		t.Text = string(append(runes[:t.cursor-1], runes[t.cursor:]...))
+		t.cursor--
+	case KeyRepeated(ebiten.KeyDelete) && t.cursor < len(runes):
+		t.Text = string(append(runes[:t.cursor], runes[t.cursor+1:]...))
+	case in.Left:
+		t.cursor = max(t.cursor-1, 0)
+	case in.Right:
+		t.cursor = min(t.cursor+1, len(runes))
+	case inpututil.IsKeyJustPressed(ebiten.KeyHome):
+		t.cursor = 0
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnd):
+		t.cursor = len(runes)
+	case control && inpututil.IsKeyJustPressed(ebiten.KeyV),
+		ebiten.IsKeyPressed(ebiten.KeyShift) && inpututil.IsKeyJustPressed(ebiten.KeyInsert):
+		limited = t.paste() || limited
+	}
+	// gauche et droite déplacent le curseur, Espace sert à la saisie : seule Entrée valide le champ
+	in.Left, in.Right, in.Activate = false, false, false
+
+	// le curseur reste visible pendant la saisie
+	t.blink++
+	if t.Text != previous || t.cursor != cursor {
+		t.blink = 0
+	}
+	if t.Text != previous {
+		t.Message = ""
+		if t.Validate != nil {
+			t.Message = t.Validate(t.Text)
+		}
+		if t.OnChange != nil {
+			t.OnChange(t.Text)
+		}
+	}
+	if limited {
+		t.Message = i18n.N("textfield.max_length", t.MaxLength)
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
+		t.Submit()
+	}
+}
+
+// Submit valide le champ : affiche le message de Validate ou appelle OnSubmit
+func (t *TextField) Submit() {
+	if t.Validate != nil {
+		if t.Message = t.Validate(t.Text); t.Message != "" {
+			return
+		}
+	}
+	if t.OnSubmit != nil {
+		t.OnSubmit(t.Text)
+	}
+}
+
+// colle le texte du presse-papiers à la position du curseur, les caractères refusés par Accept sont ignorés
+// Retourne true si le texte collé a été tronqué
+func (t *TextField) paste() bool {
+	content, err := clipboard.ReadText()
+	if err != nil {
+		log.Printf("Impossible de lire le presse-papiers: %v", err)
+		return false
+	}
+	return t.insert([]rune(strings.TrimSpace(content)))
+}
+
+func (t *TextField) Draw(screen *ebiten.Image, state State) {
+	f := face(t.TextSize)
+	palette := theme.Current().Palette
+	border := palette.Border
+	if state.Focused {
+		border = palette.Accent
+	}
+
+	padding, edge := ui.Scaled(textFieldPadding), ui.Scaled(2)
+	field := image.Rect(t.bounds.Min.X, t.bounds.Min.Y, t.bounds.Max.X, t.bounds.Min.Y+t.fieldHeight())
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(float64(field.Min.X), float64(field.Min.Y))
+	screen.DrawImage(ui.Panel(field.Dx(), field.Dy(), border), opts)
+	opts = &ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(float64(field.Min.X+edge), float64(field.Min.Y+edge))
+	screen.DrawImage(ui.Panel(field.Dx()-2*edge, field.Dy()-2*edge, palette.Board), opts)
+
+	drawFocusMarker(screen, f, field, state)
+	x := field.Min.X + padding
+	drawText(screen, f, t.Text, field, x, palette.Text)
+
+	// le curseur clignote et reste visible pendant la saisie
+	if state.Focused && (t.blink/textFieldBlink)%2 == 0 {
+		runes := []rune(t.Text)
+		caretX := x + font.MeasureString(f, string(runes[:min(t.cursor, len(runes))])).Ceil()
+		opts = &ebiten.DrawImageOptions{}
+		opts.GeoM.Translate(float64(caretX), float64(field.Min.Y+padding))
+		screen.DrawImage(ui.Panel(ui.Scaled(textFieldCaret), field.Dy()-2*padding, palette.Text), opts)
+	}
+
+	if t.Message != "" {
+		message := image.Rect(field.Min.X, field.Max.Y+padding, field.Max.X, t.bounds.Max.Y)
+		drawText(screen, face(textFieldMessages), t.Message, message, field.Min.X, palette.CollisionColor)
+	}
+}
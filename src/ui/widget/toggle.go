@@ -0,0 +1,60 @@
+package widget
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ToggleStates sont les mots qui décrivent un interrupteur désactivé puis activé à la narration des menus
+var ToggleStates = [2]string{"non", "oui"}
+
+// Toggle est un interrupteur oui/non
+type Toggle struct {
+	base
+	Label    string
+	Value    bool
+	TextSize float64
+	OnChange func(value bool)
+}
+
+// NewToggle crée un interrupteur
+func NewToggle(label string, value bool, onChange func(value bool)) *Toggle {
+	return &Toggle{Label: label, Value: value, OnChange: onChange}
+}
+
+func (t *Toggle) Focusable() bool {
+	return true
+}
+
+func (t *Toggle) text() string {
+	if t.Value {
+		return t.Label + " : [x]"
+	}
+	return t.Label + " : [ ]"
+}
+
+func (t *Toggle) Describe() string {
+	if t.Value {
+		return t.Label + " : " + ToggleStates[1]
+	}
+	return t.Label + " : " + ToggleStates[0]
+}
+
+func (t *Toggle) Size() (int, int) {
+	return measure(face(t.TextSize), t.Label+" : [x]")
+}
+
+func (t *Toggle) Update(in *Input) {
+	if in.Activate || in.Left || in.Right || in.ClickedIn(t.bounds) {
+		in.Activate, in.Left, in.Right, in.Clicked = false, false, false, false
+		t.Value = !t.Value
+		if t.OnChange != nil {
+			t.OnChange(t.Value)
+		}
+	}
+}
+
+func (t *Toggle) Draw(screen *ebiten.Image, state State) {
+	f := face(t.TextSize)
+	drawFocusMarker(screen, f, t.bounds, state)
+	drawText(screen, f, t.text(), t.bounds, t.bounds.Min.X, textColor(state))
+}
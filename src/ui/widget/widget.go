@@ -0,0 +1,119 @@
+// Package widget fournit les éléments d'interface utilisés par les menus : textes, boutons, listes, curseurs,
+// interrupteurs, champs de saisie et le panneau qui les dispose et gère le focus au clavier, à la manette et à la souris
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+
+	"snake-go/src/theme"
+	"snake-go/src/ui"
+)
+
+// Tailles de police par défaut
+const (
+	DefaultTextSize  = 24
+	DefaultTitleSize = 40
+)
+
+// Widget est un élément d'interface placé par son panneau
+type Widget interface {
+	// Size retourne la taille souhaitée du widget en pixels
+	Size() (int, int)
+	// SetBounds place le widget dans le rectangle donné
+	SetBounds(bounds image.Rectangle)
+	// Bounds retourne le rectangle occupé par le widget
+	Bounds() image.Rectangle
+	// Update traite les entrées lorsque le widget a le focus
+	Update(in *Input)
+	// Draw dessine le widget
+	Draw(screen *ebiten.Image, state State)
+}
+
+// Focusable est implémenté par les widgets qui peuvent recevoir le focus
+type Focusable interface {
+	Widget
+	Focusable() bool
+}
+
+// Describer est implémenté par les widgets que la narration des menus peut lire à voix haute
+type Describer interface {
+	// Describe retourne le texte qui décrit le widget et sa valeur
+	Describe() string
+}
+
+// Describe retourne la description d'un widget, vide s'il n'en a pas
+func Describe(w Widget) string {
+	if d, ok := w.(Describer); ok {
+		return d.Describe()
+	}
+	return ""
+}
+
+// State indique comment dessiner un widget
+type State struct {
+	Focused bool
+	Hovered bool
+}
+
+// base regroupe ce qui est commun à tous les widgets
+type base struct {
+	bounds image.Rectangle
+}
+
+func (b *base) SetBounds(bounds image.Rectangle) {
+	b.bounds = bounds
+}
+
+func (b *base) Bounds() image.Rectangle {
+	return b.bounds
+}
+
+func (b *base) Update(in *Input) {}
+
+// couleur du texte d'un widget selon son état
+func textColor(state State) color.Color {
+	palette := theme.Current().Palette
+	switch {
+	case state.Focused:
+		return palette.Accent
+	case state.Hovered:
+		return palette.Highlight
+	}
+	return palette.Text
+}
+
+// police de la taille donnée, DefaultTextSize si size vaut 0
+func face(size float64) font.Face {
+	if size == 0 {
+		size = DefaultTextSize
+	}
+	return ui.Font(size)
+}
+
+// mesure la taille d'un texte
+func measure(f font.Face, s string) (int, int) {
+	bounds := text.BoundString(f, s)
+	return bounds.Dx(), f.Metrics().Height.Ceil()
+}
+
+// dessine un texte centré verticalement dans le rectangle, à partir de son bord gauche
+func drawText(screen *ebiten.Image, f font.Face, s string, bounds image.Rectangle, x int, c color.Color) {
+	metrics := f.Metrics()
+	lineHeight := metrics.Height.Ceil()
+	baseline := bounds.Min.Y + (bounds.Dy()-lineHeight)/2 + metrics.Ascent.Ceil()
+	text.Draw(screen, s, f, x, baseline, c)
+}
+
+// dessine le marqueur de focus à gauche du widget
+func drawFocusMarker(screen *ebiten.Image, f font.Face, bounds image.Rectangle, state State) {
+	if !state.Focused {
+		return
+	}
+	width, _ := measure(f, ">")
+	drawText(screen, f, ">", bounds, bounds.Min.X-width-ui.Scaled(12), textColor(state))
+}
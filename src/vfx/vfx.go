@@ -0,0 +1,205 @@
+// Package vfx ajoute des effets visuels à la partie : particules, tremblement de l'écran, flash rouge,
+// pulsation du score et ralenti à la mort du serpent
+//
+// Les effets réagissent aux événements de la partie et utilisent leur propre générateur aléatoire,
+// pour ne pas changer le déroulement d'une partie rejouée.
+package vfx
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"snake-go/src/board"
+	"snake-go/src/config"
+	"snake-go/src/event"
+	"snake-go/src/theme"
+	"snake-go/src/ui"
+)
+
+// Ralenti à la mort du serpent : durée en ticks avant l'écran de fin de partie et vitesse des effets pendant ce temps
+const (
+	SlowMotionTicks = 60
+	SlowMotionScale = 0.3
+)
+
+// Réglages des effets, les durées sont en ticks
+const (
+	burstParticles = 18  // particules projetées quand le serpent mange
+	deathParticles = 40  // particules projetées à la mort du serpent
+	particleLife   = 30  // durée de vie d'une particule d'explosion
+	trailLife      = 14  // durée de vie d'une particule de traînée
+	trailInterval  = 5   // la traînée apparaît quand le serpent se déplace tous les trailInterval ticks ou plus vite
+	particleDrag   = 0.9 // ralentissement des particules à chaque tick
+	shakeAmplitude = 10  // décalage maximal de l'écran en pixels au début d'un tremblement
+	shakeDecay     = 0.8 // atténuation du tremblement à chaque tick
+	flashOpacity   = 0.45
+	flashDecay     = 0.03
+	pulseTicks     = 12
+	pulseScale     = 0.4 // agrandissement du score au début d'une pulsation
+)
+
+// couleur des morceaux de pomme
+var appleColor = color.RGBA{R: 214, G: 48, B: 49, A: 255}
+
+// particle est un petit carré qui s'éloigne puis s'efface, sa position est en cellules du plateau
+type particle struct {
+	x, y, vx, vy float64
+	size         float64 // côté en fraction de cellule
+	life, max    float64
+	color        color.Color
+}
+
+// Effects regroupe les effets visuels d'une partie
+type Effects struct {
+	Options   config.Effects // effets activés, pris dans la configuration au début de chaque partie
+	particles []particle
+	shake     float64 // amplitude restante du tremblement, en pixels
+	flash     float64 // opacité restante du flash rouge
+	pulse     float64 // ticks restants de la pulsation du score
+	interval  int     // ticks entre deux déplacements du serpent
+	head      image.Point
+	moved     bool // head contient la position précédente de la tête
+	rng       *rand.Rand
+}
+
+// Subscribe fait réagir les effets aux événements de la partie
+func (e *Effects) Subscribe(bus *event.Bus) {
+	event.Subscribe(bus, func(ev event.GameStarted) {
+		e.Reset()
+		e.interval = ev.Interval
+	})
+	event.Subscribe(bus, func(ev event.SpeedUp) { e.interval = ev.Interval })
+	event.Subscribe(bus, func(ev event.Moved) {
+		if e.moved && e.Options.Particles && e.interval <= trailInterval {
+			e.emit(e.head, 2, 0.03, trailLife, 0.25, theme.Current().Palette.Highlight)
+		}
+		e.head, e.moved = ev.Head, true
+	})
+	event.Subscribe(bus, func(ev event.FoodEaten) {
+		if e.Options.Particles {
+			e.emit(ev.Position, burstParticles/2, 0.15, particleLife, 0.2, appleColor)
+			e.emit(ev.Position, burstParticles/2, 0.1, particleLife, 0.15, theme.Current().Palette.Accent)
+		}
+		if e.Options.ScorePulse {
+			e.pulse = pulseTicks
+		}
+	})
+	event.Subscribe(bus, func(ev event.Died) {
+		if e.Options.Particles {
+			e.emit(ev.Position, deathParticles, 0.25, particleLife*2, 0.2, theme.Current().Palette.CollisionColor)
+		}
+		if e.Options.Shake {
+			e.shake = shakeAmplitude
+		}
+		if e.Options.Flash {
+			e.flash = flashOpacity
+		}
+	})
+	event.Subscribe(bus, func(ev event.LifeLost) { e.moved = false })
+}
+
+// Reset efface les effets en cours
+func (e *Effects) Reset() {
+	e.particles = e.particles[:0]
+	e.shake, e.flash, e.pulse = 0, 0, 0
+	e.moved = false
+}
+
+// projette des particules depuis le centre d'une cellule
+//
+// cell: la cellule d'où partent les particules
+// count: le nombre de particules
+// speed: la vitesse maximale, en cellules par tick
+// life: la durée de vie en ticks
+// size: le côté des particules, en fraction de cellule
+func (e *Effects) emit(cell image.Point, count int, speed, life, size float64, c color.Color) {
+	if e.rng == nil {
+		e.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	for i := 0; i < count; i++ {
+		angle := e.rng.Float64() * 2 * math.Pi
+		v := speed * (0.3 + 0.7*e.rng.Float64())
+		e.particles = append(e.particles, particle{
+			x: float64(cell.X) + 0.5, y: float64(cell.Y) + 0.5,
+			vx: math.Cos(angle) * v, vy: math.Sin(angle) * v,
+			size: size * (0.6 + 0.8*e.rng.Float64()),
+			life: life, max: life,
+			color: c,
+		})
+	}
+}
+
+// Update fait avancer les effets, à appeler à chaque tick pendant la partie
+//
+// timeScale: la vitesse des effets, 1 normalement et SlowMotionScale pendant le ralenti
+func (e *Effects) Update(timeScale float64) {
+	alive := e.particles[:0]
+	drag := math.Pow(particleDrag, timeScale)
+	for _, p := range e.particles {
+		p.x += p.vx * timeScale
+		p.y += p.vy * timeScale
+		p.vx *= drag
+		p.vy *= drag
+		p.life -= timeScale
+		if p.life > 0 {
+			alive = append(alive, p)
+		}
+	}
+	e.particles = alive
+
+	e.shake *= math.Pow(shakeDecay, timeScale)
+	if e.shake < 0.5 {
+		e.shake = 0
+	}
+	e.flash = max(e.flash-flashDecay*timeScale, 0)
+	e.pulse = max(e.pulse-timeScale, 0)
+}
+
+// ShakeOffset retourne le décalage du plateau dû au tremblement de l'écran, en pixels
+func (e *Effects) ShakeOffset() (float64, float64) {
+	if e.shake == 0 || e.rng == nil {
+		return 0, 0
+	}
+	return (e.rng.Float64()*2 - 1) * e.shake, (e.rng.Float64()*2 - 1) * e.shake
+}
+
+// ScoreScale retourne l'agrandissement du score, supérieur à 1 pendant une pulsation
+func (e *Effects) ScoreScale() float64 {
+	return 1 + pulseScale*math.Sin(math.Pi*e.pulse/pulseTicks)
+}
+
+// DrawParticles dessine les particules sur le plateau
+//
+// screen: l'image du plateau
+// layout: la disposition du plateau, pour placer les particules dans les cellules
+func (e *Effects) DrawParticles(screen *ebiten.Image, layout board.Layout) {
+	cell := float64(layout.CellSize)
+	pixel := ui.Panel(1, 1, color.White)
+	for _, p := range e.particles {
+		side := p.size * cell
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Scale(side, side)
+		opts.GeoM.Translate(float64(layout.X)+p.x*cell-side/2, float64(layout.Y)+p.y*cell-side/2)
+		opts.ColorScale.ScaleWithColor(p.color)
+		opts.ColorScale.ScaleAlpha(float32(p.life / p.max))
+		screen.DrawImage(pixel, opts)
+	}
+}
+
+// DrawFlash recouvre l'écran du flash rouge d'une collision
+func (e *Effects) DrawFlash(screen *ebiten.Image) {
+	if e.flash == 0 {
+		return
+	}
+	opts := &ebiten.DrawImageOptions{}
+	bounds := screen.Bounds()
+	opts.GeoM.Scale(float64(bounds.Dx()), float64(bounds.Dy()))
+	opts.ColorScale.ScaleWithColor(color.RGBA{R: 255, A: 255})
+	opts.ColorScale.ScaleAlpha(float32(e.flash))
+	screen.DrawImage(ui.Panel(1, 1, color.White), opts)
+}